@@ -0,0 +1,181 @@
+package okx
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/ws"
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/ws/business"
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/ws/private"
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/ws/public"
+)
+
+// The websocket endpoints of OKX, also the endpointType of their WSClient.
+const (
+	EndpointPublic   = "public"
+	EndpointPrivate  = "private"
+	EndpointBusiness = "business"
+)
+
+// ChannelSpec describes how an OKX websocket channel is subscribed and
+// decoded.
+type ChannelSpec struct {
+	// Name is the channel name, or its prefix if Prefix is set, e.g. "candle"
+	// for candle1m, candle5m and every other bar size.
+	Name   string
+	Prefix bool
+	// Endpoint is EndpointPublic, EndpointPrivate or EndpointBusiness.
+	Endpoint string
+	// Auth is set for channels that need a login. Every private channel does.
+	Auth bool
+	// ArgKeys are the args, besides channel, a subscription must set:
+	// "instId", "instType", "instFamily" or "uly".
+	ArgKeys []string
+	// StaleAfter is how long the channel may stay silent before the
+	// watchdog considers it stale, zero for channels that legitimately go
+	// quiet, such as trades or order updates. Market data only pushes on
+	// change, so it is set for the quietest instruments rather than the
+	// busiest.
+	StaleAfter time.Duration
+	// Decode turns a push into the event handed to listeners. Pushes of
+	// channels without one are delivered as raw []byte.
+	Decode func(message []byte) (interface{}, error)
+}
+
+// NeedsAuth reports whether subscribing requires a login.
+func (s ChannelSpec) NeedsAuth() bool {
+	return s.Auth || s.Endpoint == EndpointPrivate
+}
+
+// Validate checks that arg sets every key the channel is subscribed by.
+func (s ChannelSpec) Validate(arg ws.Args) error {
+	for _, key := range s.ArgKeys {
+		var value string
+		switch key {
+		case "instId":
+			value = arg.InstId
+		case "instType":
+			value = arg.InstType
+		case "instFamily":
+			value = arg.InstFamily
+		case "uly":
+			value = arg.Uly
+		default:
+			return fmt.Errorf("channel %s has unknown arg key %q", arg.Channel, key)
+		}
+		if value == "" {
+			return fmt.Errorf("channel %s requires %s", arg.Channel, key)
+		}
+	}
+	return nil
+}
+
+// DecodeAs returns a ChannelSpec decoder that unmarshals pushes into a *T.
+func DecodeAs[T any]() func(message []byte) (interface{}, error) {
+	return func(message []byte) (interface{}, error) {
+		event := new(T)
+		if err := json.Unmarshal(message, event); err != nil {
+			return nil, err
+		}
+		return event, nil
+	}
+}
+
+// ChannelCatalog maps channel names to their spec. Channels it does not
+// know are served by the public endpoint without decoding.
+type ChannelCatalog struct {
+	mu       sync.RWMutex
+	exact    map[string]ChannelSpec
+	prefixes []ChannelSpec
+}
+
+func NewChannelCatalog(specs ...ChannelSpec) *ChannelCatalog {
+	c := &ChannelCatalog{exact: make(map[string]ChannelSpec)}
+	for _, spec := range specs {
+		c.Register(spec)
+	}
+	return c
+}
+
+// Register adds a channel or replaces the spec of one already known.
+func (c *ChannelCatalog) Register(spec ChannelSpec) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !spec.Prefix {
+		c.exact[spec.Name] = spec
+		return
+	}
+	for i, other := range c.prefixes {
+		if other.Name == spec.Name {
+			c.prefixes[i] = spec
+			return
+		}
+	}
+	c.prefixes = append(c.prefixes, spec)
+	// longest prefix first, so mark-price-candle wins over a shorter match
+	sort.Slice(c.prefixes, func(i, j int) bool {
+		return len(c.prefixes[i].Name) > len(c.prefixes[j].Name)
+	})
+}
+
+// Lookup returns the spec of channel, matching exact names before prefixes.
+func (c *ChannelCatalog) Lookup(channel string) (ChannelSpec, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if spec, ok := c.exact[channel]; ok {
+		return spec, true
+	}
+	for _, spec := range c.prefixes {
+		if strings.HasPrefix(channel, spec.Name) {
+			return spec, true
+		}
+	}
+	return ChannelSpec{Name: channel, Endpoint: EndpointPublic}, false
+}
+
+// Channels is the catalog used by every client. Register channels OKX adds
+// before the SDK knows them.
+var Channels = NewChannelCatalog(
+	// public
+	ChannelSpec{Name: "tickers", Endpoint: EndpointPublic, ArgKeys: []string{"instId"}, StaleAfter: 5 * time.Minute, Decode: DecodeAs[public.TickerEvent]()},
+	ChannelSpec{Name: "trades", Endpoint: EndpointPublic, ArgKeys: []string{"instId"}, Decode: DecodeAs[public.TradeEvent]()},
+	ChannelSpec{Name: "books", Endpoint: EndpointPublic, ArgKeys: []string{"instId"}, StaleAfter: 5 * time.Minute, Decode: DecodeAs[public.OrderBookEvent]()},
+	ChannelSpec{Name: "books5", Endpoint: EndpointPublic, ArgKeys: []string{"instId"}, StaleAfter: 5 * time.Minute, Decode: DecodeAs[public.OrderBookEvent]()},
+	ChannelSpec{Name: "bbo-tbt", Endpoint: EndpointPublic, ArgKeys: []string{"instId"}, StaleAfter: 5 * time.Minute, Decode: DecodeAs[public.OrderBookEvent]()},
+	ChannelSpec{Name: "books-l2-tbt", Endpoint: EndpointPublic, Auth: true, ArgKeys: []string{"instId"}, StaleAfter: 5 * time.Minute, Decode: DecodeAs[public.OrderBookEvent]()},
+	ChannelSpec{Name: "books50-l2-tbt", Endpoint: EndpointPublic, Auth: true, ArgKeys: []string{"instId"}, StaleAfter: 5 * time.Minute, Decode: DecodeAs[public.OrderBookEvent]()},
+	ChannelSpec{Name: "instruments", Endpoint: EndpointPublic, ArgKeys: []string{"instType"}},
+	ChannelSpec{Name: "open-interest", Endpoint: EndpointPublic, ArgKeys: []string{"instId"}, StaleAfter: 5 * time.Minute},
+	ChannelSpec{Name: "funding-rate", Endpoint: EndpointPublic, ArgKeys: []string{"instId"}, StaleAfter: 5 * time.Minute},
+	ChannelSpec{Name: "price-limit", Endpoint: EndpointPublic, ArgKeys: []string{"instId"}},
+	ChannelSpec{Name: "opt-summary", Endpoint: EndpointPublic, ArgKeys: []string{"instFamily"}},
+	ChannelSpec{Name: "estimated-price", Endpoint: EndpointPublic, ArgKeys: []string{"instType"}},
+	ChannelSpec{Name: "mark-price", Endpoint: EndpointPublic, ArgKeys: []string{"instId"}, StaleAfter: time.Minute},
+	ChannelSpec{Name: "index-tickers", Endpoint: EndpointPublic, ArgKeys: []string{"instId"}, StaleAfter: 3 * time.Minute},
+	ChannelSpec{Name: "liquidation-orders", Endpoint: EndpointPublic, ArgKeys: []string{"instType"}},
+	// private
+	ChannelSpec{Name: "account", Endpoint: EndpointPrivate},
+	ChannelSpec{Name: "positions", Endpoint: EndpointPrivate, ArgKeys: []string{"instType"}},
+	ChannelSpec{Name: "balance_and_position", Endpoint: EndpointPrivate},
+	ChannelSpec{Name: "liquidation-warning", Endpoint: EndpointPrivate, ArgKeys: []string{"instType"}},
+	ChannelSpec{Name: "account-greeks", Endpoint: EndpointPrivate},
+	ChannelSpec{Name: "orders", Endpoint: EndpointPrivate, ArgKeys: []string{"instType"}, Decode: DecodeAs[private.OrderEvent]()},
+	ChannelSpec{Name: "fills", Endpoint: EndpointPrivate},
+	// business
+	ChannelSpec{Name: "candle", Prefix: true, Endpoint: EndpointBusiness, ArgKeys: []string{"instId"}, StaleAfter: 5 * time.Minute, Decode: DecodeAs[business.CandleEvent]()},
+	ChannelSpec{Name: "mark-price-candle", Prefix: true, Endpoint: EndpointBusiness, ArgKeys: []string{"instId"}, StaleAfter: 5 * time.Minute},
+	ChannelSpec{Name: "index-candle", Prefix: true, Endpoint: EndpointBusiness, ArgKeys: []string{"instId"}, StaleAfter: 5 * time.Minute},
+	ChannelSpec{Name: "trades-all", Endpoint: EndpointBusiness, ArgKeys: []string{"instId"}, Decode: DecodeAs[public.TradeEvent]()},
+	ChannelSpec{Name: "orders-algo", Endpoint: EndpointBusiness, Auth: true, ArgKeys: []string{"instType"}},
+	ChannelSpec{Name: "algo-advance", Endpoint: EndpointBusiness, Auth: true, ArgKeys: []string{"instType"}},
+	ChannelSpec{Name: "deposit-info", Endpoint: EndpointBusiness, Auth: true},
+	ChannelSpec{Name: "withdrawal-info", Endpoint: EndpointBusiness, Auth: true},
+	ChannelSpec{Name: "grid-orders-spot", Endpoint: EndpointBusiness, Auth: true, ArgKeys: []string{"instType"}},
+	ChannelSpec{Name: "grid-orders-contract", Endpoint: EndpointBusiness, Auth: true, ArgKeys: []string{"instType"}},
+	ChannelSpec{Name: "grid-positions", Endpoint: EndpointBusiness, Auth: true},
+	ChannelSpec{Name: "grid-sub-orders", Endpoint: EndpointBusiness, Auth: true},
+)
@@ -1,6 +1,8 @@
 package okx
 
 import (
+	"sync"
+
 	"cadenza-market-connector-okx/pkg/go-okx-api/common"
 )
 
@@ -13,13 +15,40 @@ type Configuration struct {
 	SecretKey string 
 	OkxPassphrase string
 	AutoReconnect bool
+	// ReconnectPolicy is used when AutoReconnect is set, DefaultReconnectPolicy
+	// if nil.
+	ReconnectPolicy *ReconnectPolicy
 	DebugMode bool
+	// Tag is the broker tag set on every order placed through the client, up
+	// to 16 alphanumeric characters. An invalid tag is ignored.
+	Tag string
+	// ClOrdIdPrefix enables generated clOrdIds for orders placed without one.
+	ClOrdIdPrefix string
+	// Pool shards websocket subscriptions over several connections per
+	// endpoint. The zero value uses one connection each.
+	Pool PoolConfig
+	// Throttle paces websocket requests, DefaultThrottleConfig if nil.
+	Throttle *ThrottleConfig
+	// Logger receives the logs of the REST and websocket clients, which are
+	// discarded if nil. Use NewSlogLogger to log through log/slog.
+	Logger Logger
+	// Verbose logs every websocket message received at debug level.
+	Verbose bool
+	// Metrics records REST and websocket activity, see NewInMemoryMetrics.
+	Metrics Metrics
+	// Watchdog resubscribes websocket streams that stop updating, disabled if
+	// nil. See DefaultWatchdogConfig.
+	Watchdog *WatchdogConfig
 }
 
 type Client struct {
 	Configuration *Configuration
 	Rest *RestClient
 	Ws *OKXWsClient
+	DeadMansSwitch *DeadMansSwitch
+
+	// mu guards DeadMansSwitch so concurrent starts cannot leak a refresher.
+	mu sync.Mutex
 }
 
 
@@ -27,7 +56,41 @@ type Client struct {
 func NewClient(configuration *Configuration) *Client{
 	auth := common.NewAuth(configuration.ApiKey,configuration.SecretKey , configuration.OkxPassphrase , configuration.DebugMode)
 	restClient := NewRestClient("", auth ,nil)
+	restClient.Logger = configuration.Logger
+	restClient.Metrics = configuration.Metrics
+	if err := common.ValidateTag(configuration.Tag); err != nil {
+		restClient.log().Warn("ignoring tag", "tag", configuration.Tag, "error", err)
+	} else {
+		restClient.Tag = configuration.Tag
+	}
+	if configuration.ClOrdIdPrefix != "" {
+		ids, err := common.NewClOrdIdGenerator(configuration.ClOrdIdPrefix)
+		if err != nil {
+			restClient.log().Warn("ignoring clOrdId prefix", "prefix", configuration.ClOrdIdPrefix, "error", err)
+		} else {
+			restClient.ClOrdIds = ids
+		}
+	}
 	wsClient := NewOKXWsClient(auth)
+	wsClient.SetLogger(configuration.Logger)
+	wsClient.SetVerbose(configuration.Verbose)
+	wsClient.SetMetrics(configuration.Metrics)
+	wsClient.SetWatchdog(configuration.Watchdog)
+	wsClient.Private.Tag = restClient.Tag
+	wsClient.Private.ClOrdIds = restClient.ClOrdIds
+	if configuration.AutoReconnect {
+		policy := configuration.ReconnectPolicy
+		if policy == nil {
+			policy = DefaultReconnectPolicy()
+		}
+		wsClient.SetReconnectPolicy(policy)
+	} else {
+		wsClient.SetReconnectPolicy(nil)
+	}
+	if configuration.Throttle != nil {
+		wsClient.SetThrottle(configuration.Throttle)
+	}
+	wsClient.SetPoolConfig(configuration.Pool)
 
 
 
@@ -39,3 +102,36 @@ func NewClient(configuration *Configuration) *Client{
 
 }
 
+// StartDeadMansSwitch arms a cancel-all-after countdown that is refreshed for
+// as long as the REST API and every private websocket connection stay
+// healthy. A switch started before is stopped first. It is stopped by Close.
+func (c *Client) StartDeadMansSwitch(config DeadMansSwitchConfig) (*DeadMansSwitch, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.DeadMansSwitch != nil {
+		if err := c.DeadMansSwitch.Stop(); err != nil {
+			return nil, err
+		}
+	}
+	d := NewDeadMansSwitch(c.Rest, c.Ws.Pool(EndpointPrivate), config)
+	if err := d.Start(); err != nil {
+		return nil, err
+	}
+	c.DeadMansSwitch = d
+	return d, nil
+}
+
+// Close stops the dead man's switch, if any, before closing every websocket
+// connection.
+func (c *Client) Close() error {
+	var firstErr error
+	c.mu.Lock()
+	if c.DeadMansSwitch != nil {
+		firstErr = c.DeadMansSwitch.Stop()
+	}
+	c.mu.Unlock()
+	if err := c.Ws.Close(); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return firstErr
+}
@@ -0,0 +1,86 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	MaxClOrdIdLength = 32
+	clOrdIdSeqLength = 11
+	// MaxClOrdIdPrefixLength leaves room for the fixed width sequence.
+	MaxClOrdIdPrefixLength = MaxClOrdIdLength - clOrdIdSeqLength
+	MaxTagLength           = 16
+)
+
+// ClOrdIdGenerator issues OKX clOrdIds made of a strategy prefix followed by a
+// fixed width base36 sequence. The sequence is seeded from the wall clock in
+// microseconds, so ids keep increasing across restarts of the process.
+type ClOrdIdGenerator struct {
+	prefix string
+	mu     sync.Mutex
+	last   uint64
+}
+
+func NewClOrdIdGenerator(prefix string) (*ClOrdIdGenerator, error) {
+	if len(prefix) > MaxClOrdIdPrefixLength {
+		return nil, fmt.Errorf("clOrdId prefix %q longer than %d characters", prefix, MaxClOrdIdPrefixLength)
+	}
+	if !IsAlphanumeric(prefix) {
+		return nil, fmt.Errorf("clOrdId prefix %q must be alphanumeric", prefix)
+	}
+	return &ClOrdIdGenerator{prefix: prefix}, nil
+}
+
+func (g *ClOrdIdGenerator) Prefix() string {
+	return g.prefix
+}
+
+func (g *ClOrdIdGenerator) Next() string {
+	g.mu.Lock()
+	seq := uint64(time.Now().UnixNano() / int64(time.Microsecond))
+	if seq <= g.last {
+		seq = g.last + 1
+	}
+	g.last = seq
+	g.mu.Unlock()
+
+	s := strconv.FormatUint(seq, 36)
+	if len(s) < clOrdIdSeqLength {
+		s = strings.Repeat("0", clOrdIdSeqLength-len(s)) + s
+	}
+	return g.prefix + s
+}
+
+// ClOrdIdPrefix returns the strategy prefix of a clOrdId issued by a
+// ClOrdIdGenerator.
+func ClOrdIdPrefix(clOrdId string) string {
+	if len(clOrdId) < clOrdIdSeqLength {
+		return ""
+	}
+	return clOrdId[:len(clOrdId)-clOrdIdSeqLength]
+}
+
+// ValidateTag checks that tag is an order tag OKX accepts: up to 16
+// alphanumeric characters.
+func ValidateTag(tag string) error {
+	if len(tag) > MaxTagLength {
+		return fmt.Errorf("tag %q longer than %d characters", tag, MaxTagLength)
+	}
+	if !IsAlphanumeric(tag) {
+		return fmt.Errorf("tag %q must be alphanumeric", tag)
+	}
+	return nil
+}
+
+func IsAlphanumeric(s string) bool {
+	for _, r := range s {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
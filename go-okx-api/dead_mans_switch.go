@@ -0,0 +1,249 @@
+package okx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/rest/public"
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/rest/trade"
+)
+
+const (
+	MinCancelAllAfterTimeout = 10 * time.Second
+	MaxCancelAllAfterTimeout = 120 * time.Second
+)
+
+type DeadMansSwitchState int
+
+const (
+	DeadMansSwitchIdle DeadMansSwitchState = iota
+	DeadMansSwitchArmed
+	DeadMansSwitchTripped
+	DeadMansSwitchStopped
+)
+
+func (s DeadMansSwitchState) String() string {
+	switch s {
+	case DeadMansSwitchIdle:
+		return "idle"
+	case DeadMansSwitchArmed:
+		return "armed"
+	case DeadMansSwitchTripped:
+		return "tripped"
+	case DeadMansSwitchStopped:
+		return "stopped"
+	}
+	return "unknown"
+}
+
+type DeadMansSwitchConfig struct {
+	// Timeout is the cancel-all-after countdown sent to OKX, a whole number of
+	// seconds between 10s and 120s.
+	Timeout time.Duration
+	// RefreshInterval defaults to a third of Timeout and must be shorter than
+	// it, or the countdown expires between refreshes.
+	RefreshInterval time.Duration
+	// Tag restricts the cancellation to orders placed with this tag.
+	Tag string
+	// CancelOnStop leaves the countdown running when the switch is stopped, so
+	// a graceful shutdown also cancels all orders. Otherwise Stop disarms it.
+	CancelOnStop bool
+	// HealthChecks run before every refresh in addition to the REST probe
+	// and the check of every private websocket connection. Any error
+	// withholds the refresh.
+	HealthChecks []func() error
+}
+
+// DeadMansSwitch keeps refreshing OKX's cancel-all-after countdown while the
+// client is healthy: the REST API answers and every connection of the private
+// websocket pool is up. Once a health check or the refresh itself fails the
+// countdown is left to expire and OKX cancels all pending orders.
+type DeadMansSwitch struct {
+	rest    *RestClient
+	private *ConnectionPool
+	config  DeadMansSwitchConfig
+
+	mu          sync.Mutex
+	state       DeadMansSwitchState
+	triggerTime time.Time
+	lastErr     error
+	cancel      context.CancelFunc
+	done        chan struct{}
+}
+
+// NewDeadMansSwitch returns a switch refreshed through rest. A nil private
+// pool leaves the websocket out of the health checks.
+func NewDeadMansSwitch(rest *RestClient, private *ConnectionPool, config DeadMansSwitchConfig) *DeadMansSwitch {
+	if config.RefreshInterval <= 0 {
+		config.RefreshInterval = config.Timeout / 3
+	}
+	return &DeadMansSwitch{
+		rest:    rest,
+		private: private,
+		config:  config,
+		state:   DeadMansSwitchIdle,
+	}
+}
+
+// Start arms the countdown and begins refreshing it in the background.
+func (d *DeadMansSwitch) Start() error {
+	if d.config.Timeout < MinCancelAllAfterTimeout || d.config.Timeout > MaxCancelAllAfterTimeout {
+		return fmt.Errorf("cancel-all-after timeout must be between %v and %v, got %v", MinCancelAllAfterTimeout, MaxCancelAllAfterTimeout, d.config.Timeout)
+	}
+	if d.config.Timeout%time.Second != 0 {
+		return fmt.Errorf("cancel-all-after timeout must be a whole number of seconds, got %v", d.config.Timeout)
+	}
+	if d.config.RefreshInterval >= d.config.Timeout {
+		return fmt.Errorf("refresh interval %v must be shorter than the cancel-all-after timeout %v", d.config.RefreshInterval, d.config.Timeout)
+	}
+
+	d.mu.Lock()
+	if d.cancel != nil {
+		d.mu.Unlock()
+		return errors.New("dead man's switch already started")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	d.done = make(chan struct{})
+	d.mu.Unlock()
+
+	if err := d.refresh(); err != nil {
+		d.mu.Lock()
+		d.cancel = nil
+		d.mu.Unlock()
+		cancel()
+		return err
+	}
+
+	go d.run(ctx)
+	return nil
+}
+
+// Stop ends the refresh loop and, unless CancelOnStop is set, disarms the
+// countdown on OKX.
+func (d *DeadMansSwitch) Stop() error {
+	d.mu.Lock()
+	if d.cancel == nil {
+		d.mu.Unlock()
+		return nil
+	}
+	d.cancel()
+	d.cancel = nil
+	done := d.done
+	d.mu.Unlock()
+
+	<-done
+
+	var err error
+	if !d.config.CancelOnStop {
+		err = d.send("0")
+	}
+
+	d.mu.Lock()
+	d.state = DeadMansSwitchStopped
+	d.triggerTime = time.Time{}
+	d.mu.Unlock()
+	return err
+}
+
+func (d *DeadMansSwitch) State() DeadMansSwitchState {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.state
+}
+
+// TriggerTime is the time OKX will cancel all orders if no further refresh
+// succeeds. It is zero while the switch is not armed.
+func (d *DeadMansSwitch) TriggerTime() time.Time {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.triggerTime
+}
+
+// LastError returns the reason the switch last tripped.
+func (d *DeadMansSwitch) LastError() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.lastErr
+}
+
+func (d *DeadMansSwitch) run(ctx context.Context) {
+	defer close(d.done)
+	ticker := time.NewTicker(d.config.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := d.refresh(); err != nil {
+				d.rest.log().Error("dead man's switch tripped", "error", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// refresh re-arms the countdown if every health check passes and records the
+// outcome in the switch state.
+func (d *DeadMansSwitch) refresh() error {
+	err := d.checkHealth()
+	if err == nil {
+		err = d.send(strconv.Itoa(int(d.config.Timeout / time.Second)))
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err != nil {
+		d.state = DeadMansSwitchTripped
+		d.lastErr = err
+		return err
+	}
+	d.state = DeadMansSwitchArmed
+	return nil
+}
+
+func (d *DeadMansSwitch) checkHealth() error {
+	if err := d.rest.Do(public.NewGetSystemTime()); err != nil {
+		return fmt.Errorf("REST API unhealthy: %v", err)
+	}
+	if d.private != nil {
+		for i, shard := range d.private.Shards() {
+			if !shard.IsConnected() {
+				return fmt.Errorf("private websocket connection %d not connected", i)
+			}
+		}
+	}
+	for _, check := range d.config.HealthChecks {
+		if err := check(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *DeadMansSwitch) send(timeout string) error {
+	req, resp := trade.NewCancelAllAfter(&trade.CancelAllAfterParam{
+		TimeOut: timeout,
+		Tag:     d.config.Tag,
+	})
+	if err := d.rest.Do(req, resp); err != nil {
+		return fmt.Errorf("cancel-all-after: %v", err)
+	}
+
+	data := resp.(*trade.CancelAllAfterResponse).Data
+	if len(data) > 0 {
+		triggerTime, _ := strconv.ParseInt(data[0].TriggerTime, 10, 64)
+		d.mu.Lock()
+		if triggerTime > 0 {
+			d.triggerTime = time.Unix(0, triggerTime*int64(time.Millisecond))
+		} else {
+			d.triggerTime = time.Time{}
+		}
+		d.mu.Unlock()
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package okx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cadenza-market-connector-okx/pkg/go-okx-api/common"
+)
+
+func TestDeadMansSwitchValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		config DeadMansSwitchConfig
+	}{
+		{"timeout too short", DeadMansSwitchConfig{Timeout: 5 * time.Second}},
+		{"timeout too long", DeadMansSwitchConfig{Timeout: 121 * time.Second}},
+		{"fractional timeout", DeadMansSwitchConfig{Timeout: 10500 * time.Millisecond}},
+		{"refresh not shorter than timeout", DeadMansSwitchConfig{Timeout: 10 * time.Second, RefreshInterval: 10 * time.Second}},
+	}
+	for _, tt := range tests {
+		if err := NewDeadMansSwitch(nil, nil, tt.config).Start(); err == nil {
+			t.Errorf("%s: started", tt.name)
+		}
+	}
+}
+
+func TestDeadMansSwitchProbesRest(t *testing.T) {
+	var healthy atomic.Bool
+	var refreshes atomic.Int32
+	healthy.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v5/public/time" && !healthy.Load():
+			w.Write([]byte(`{"code":"50001","msg":"service temporarily unavailable","data":[]}`))
+		case r.URL.Path == "/api/v5/public/time":
+			w.Write([]byte(`{"code":"0","msg":"","data":[{"ts":"1700000000000"}]}`))
+		case strings.HasSuffix(r.URL.Path, "/cancel-all-after"):
+			refreshes.Add(1)
+			w.Write([]byte(`{"code":"0","msg":"","data":[{"triggerTime":"1700000060000","tag":"","ts":"1700000000000"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	rest := NewRestClient(server.URL, common.Auth{}, nil)
+	d := NewDeadMansSwitch(rest, nil, DeadMansSwitchConfig{Timeout: 60 * time.Second, RefreshInterval: 50 * time.Second, CancelOnStop: true})
+	if err := d.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer d.Stop()
+	if d.State() != DeadMansSwitchArmed || refreshes.Load() != 1 {
+		t.Fatalf("switch %s after %d refreshes, want armed after 1", d.State(), refreshes.Load())
+	}
+
+	healthy.Store(false)
+	if err := d.refresh(); err == nil {
+		t.Fatal("refreshed while the REST API is unhealthy")
+	}
+	if d.State() != DeadMansSwitchTripped || refreshes.Load() != 1 {
+		t.Fatalf("switch %s after %d refreshes, want tripped without a refresh", d.State(), refreshes.Load())
+	}
+}
@@ -0,0 +1,207 @@
+package okx
+
+import (
+	"reflect"
+	"sync"
+
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/ws"
+)
+
+// DefaultQueueSize is the capacity of a delivery queue configured without one.
+const DefaultQueueSize = 64
+
+// QueuePolicy decides what happens to an event published while a listener's
+// queue is full.
+type QueuePolicy int
+
+const (
+	// QueueBlock makes the connection wait for room, so nothing is lost but a
+	// slow listener delays every channel of the connection.
+	QueueBlock QueuePolicy = iota
+	// QueueDropOldest discards the oldest queued event.
+	QueueDropOldest
+	// QueueConflate keeps only the latest queued event per ws.Args, i.e. per
+	// channel and instrument, which suits tickers and top-of-book. Events
+	// without an arg, such as raw []byte payloads, are queued as they are. If
+	// the queue still fills up with distinct args the oldest is dropped.
+	QueueConflate
+)
+
+func (p QueuePolicy) String() string {
+	switch p {
+	case QueueBlock:
+		return "block"
+	case QueueDropOldest:
+		return "drop_oldest"
+	case QueueConflate:
+		return "conflate"
+	}
+	return "unknown"
+}
+
+type QueueConfig struct {
+	// Size is the number of events held, DefaultQueueSize if zero.
+	Size   int
+	Policy QueuePolicy
+}
+
+// QueueStats counts what happened to the events of a queued listener.
+type QueueStats struct {
+	Delivered uint64
+	Dropped   uint64
+	Conflated uint64
+	Pending   int
+}
+
+func (s QueueStats) add(other QueueStats) QueueStats {
+	return QueueStats{
+		Delivered: s.Delivered + other.Delivered,
+		Dropped:   s.Dropped + other.Dropped,
+		Conflated: s.Conflated + other.Conflated,
+		Pending:   s.Pending + other.Pending,
+	}
+}
+
+type queuedEvent struct {
+	// key is the arg the event is conflated by, the zero value for events
+	// that are never conflated.
+	key   ws.Args
+	event interface{}
+}
+
+// deliveryQueue decouples a listener from the connection reading its events.
+// Events are handed to deliver on a goroutine of its own.
+type deliveryQueue struct {
+	config   QueueConfig
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	items    []queuedEvent
+	closed   bool
+	stats    QueueStats
+}
+
+func newDeliveryQueue(config QueueConfig, deliver func(event interface{})) *deliveryQueue {
+	if config.Size <= 0 {
+		config.Size = DefaultQueueSize
+	}
+	q := &deliveryQueue{config: config}
+	q.notEmpty = sync.NewCond(&q.mu)
+	q.notFull = sync.NewCond(&q.mu)
+	go q.run(deliver)
+	return q
+}
+
+func (q *deliveryQueue) push(event interface{}) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return
+	}
+
+	var key ws.Args
+	if q.config.Policy == QueueConflate {
+		key = eventArgs(event)
+	}
+	if key != (ws.Args{}) {
+		for i := range q.items {
+			if q.items[i].key == key {
+				q.items[i].event = event
+				q.stats.Conflated++
+				return
+			}
+		}
+	}
+
+	if q.config.Policy == QueueBlock {
+		for len(q.items) >= q.config.Size && !q.closed {
+			q.notFull.Wait()
+		}
+		if q.closed {
+			return
+		}
+	} else if len(q.items) >= q.config.Size {
+		q.items = q.items[1:]
+		q.stats.Dropped++
+	}
+	q.items = append(q.items, queuedEvent{key: key, event: event})
+	q.notEmpty.Signal()
+}
+
+func (q *deliveryQueue) run(deliver func(event interface{})) {
+	for {
+		q.mu.Lock()
+		for len(q.items) == 0 && !q.closed {
+			q.notEmpty.Wait()
+		}
+		if q.closed {
+			q.mu.Unlock()
+			return
+		}
+		item := q.items[0]
+		q.items = q.items[1:]
+		q.notFull.Signal()
+		q.mu.Unlock()
+
+		deliver(item.event)
+
+		q.mu.Lock()
+		q.stats.Delivered++
+		q.mu.Unlock()
+	}
+}
+
+// close stops delivery and discards whatever is still queued.
+func (q *deliveryQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.items = nil
+	q.notEmpty.Broadcast()
+	q.notFull.Broadcast()
+}
+
+func (q *deliveryQueue) Stats() QueueStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	stats := q.stats
+	stats.Pending = len(q.items)
+	return stats
+}
+
+var argsType = reflect.TypeOf(ws.Args{})
+
+// eventArgs returns the Arg field of an event model, so events of one
+// listener can be conflated per channel and instrument. It is the zero value
+// for events without one.
+func eventArgs(event interface{}) ws.Args {
+	v := reflect.Indirect(reflect.ValueOf(event))
+	if v.Kind() != reflect.Struct {
+		return ws.Args{}
+	}
+	if arg := v.FieldByName("Arg"); arg.IsValid() && arg.Type() == argsType {
+		return arg.Interface().(ws.Args)
+	}
+	return ws.Args{}
+}
+
+// eventDataField returns the named string field of the first item in an
+// event model's Data, or "" if there is none.
+func eventDataField(event interface{}, name string) string {
+	v := reflect.Indirect(reflect.ValueOf(event))
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	data := v.FieldByName("Data")
+	if !data.IsValid() || data.Kind() != reflect.Slice || data.Len() == 0 {
+		return ""
+	}
+	item := reflect.Indirect(data.Index(0))
+	if item.Kind() != reflect.Struct {
+		return ""
+	}
+	if field := item.FieldByName(name); field.IsValid() && field.Kind() == reflect.String {
+		return field.String()
+	}
+	return ""
+}
@@ -0,0 +1,213 @@
+package okx
+
+import (
+	"testing"
+	"time"
+
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/ws"
+)
+
+type queueTestEvent struct {
+	Arg ws.Args
+	N   int
+}
+
+func instEvent(instId string, n int) *queueTestEvent {
+	return &queueTestEvent{Arg: ws.Args{Channel: "tickers", InstId: instId}, N: n}
+}
+
+// gatedQueue returns a queue whose deliveries are reported on the returned
+// channel and then held until gate is closed.
+func gatedQueue(config QueueConfig) (*deliveryQueue, chan interface{}, chan struct{}) {
+	delivered := make(chan interface{}, 16)
+	gate := make(chan struct{})
+	q := newDeliveryQueue(config, func(event interface{}) {
+		delivered <- event
+		<-gate
+	})
+	return q, delivered, gate
+}
+
+func receive(t *testing.T, delivered chan interface{}) interface{} {
+	t.Helper()
+	select {
+	case event := <-delivered:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("nothing delivered")
+		return nil
+	}
+}
+
+func TestDeliveryQueueDropOldest(t *testing.T) {
+	q, delivered, gate := gatedQueue(QueueConfig{Size: 2, Policy: QueueDropOldest})
+	defer q.close()
+
+	q.push(1)
+	if got := receive(t, delivered); got != 1 {
+		t.Fatalf("delivered %v first", got)
+	}
+	q.push(2)
+	q.push(3)
+	q.push(4)
+	if stats := q.Stats(); stats.Dropped != 1 || stats.Pending != 2 {
+		t.Fatalf("stats %+v, want 1 dropped and 2 pending", stats)
+	}
+
+	close(gate)
+	for _, want := range []int{3, 4} {
+		if got := receive(t, delivered); got != want {
+			t.Fatalf("delivered %v, want %d", got, want)
+		}
+	}
+	eventually(t, func() bool { return q.Stats().Delivered == 3 }, "delivered count %+v", q.Stats())
+}
+
+func TestDeliveryQueueConflate(t *testing.T) {
+	q, delivered, gate := gatedQueue(QueueConfig{Size: 2, Policy: QueueConflate})
+	defer q.close()
+
+	q.push(instEvent("BTC-USDT", 1))
+	receive(t, delivered)
+	q.push(instEvent("BTC-USDT", 2))
+	q.push(instEvent("ETH-USDT", 1))
+	q.push(instEvent("BTC-USDT", 3))
+	if stats := q.Stats(); stats.Conflated != 1 || stats.Dropped != 0 || stats.Pending != 2 {
+		t.Fatalf("stats %+v, want 1 conflated and 2 pending", stats)
+	}
+	// a third instrument no longer fits and evicts the oldest
+	q.push(instEvent("SOL-USDT", 1))
+	if stats := q.Stats(); stats.Dropped != 1 || stats.Pending != 2 {
+		t.Fatalf("stats %+v, want 1 dropped and 2 pending", stats)
+	}
+
+	close(gate)
+	for _, want := range []string{"ETH-USDT", "SOL-USDT"} {
+		got := receive(t, delivered).(*queueTestEvent)
+		if got.Arg.InstId != want {
+			t.Fatalf("delivered %s, want %s", got.Arg.InstId, want)
+		}
+	}
+}
+
+func TestDeliveryQueueConflateKeepsLatest(t *testing.T) {
+	q, delivered, gate := gatedQueue(QueueConfig{Size: 4, Policy: QueueConflate})
+	defer q.close()
+
+	q.push(instEvent("BTC-USDT", 1))
+	receive(t, delivered)
+	for n := 2; n <= 5; n++ {
+		q.push(instEvent("BTC-USDT", n))
+	}
+	close(gate)
+	if got := receive(t, delivered).(*queueTestEvent); got.N != 5 {
+		t.Fatalf("delivered update %d, want the latest", got.N)
+	}
+	if stats := q.Stats(); stats.Conflated != 3 {
+		t.Fatalf("stats %+v, want 3 conflated", stats)
+	}
+}
+
+func TestDeliveryQueueConflatesPerChannel(t *testing.T) {
+	q, delivered, gate := gatedQueue(QueueConfig{Size: 4, Policy: QueueConflate})
+	defer q.close()
+
+	q.push(instEvent("BTC-USDT", 1))
+	receive(t, delivered)
+	books := &queueTestEvent{Arg: ws.Args{Channel: "books5", InstId: "BTC-USDT"}, N: 2}
+	q.push(books)
+	q.push(instEvent("BTC-USDT", 3))
+	q.push(instEvent("BTC-USDT", 4))
+	if stats := q.Stats(); stats.Conflated != 1 || stats.Pending != 2 {
+		t.Fatalf("stats %+v, want 1 conflated and 2 pending", stats)
+	}
+
+	close(gate)
+	if got := receive(t, delivered); got != books {
+		t.Fatalf("delivered %+v, want the books5 event", got)
+	}
+	if got := receive(t, delivered).(*queueTestEvent); got.Arg.Channel != "tickers" || got.N != 4 {
+		t.Fatalf("delivered %+v, want the latest ticker", got)
+	}
+}
+
+func TestDeliveryQueueConflateKeepsEventsWithoutArgs(t *testing.T) {
+	q, delivered, gate := gatedQueue(QueueConfig{Size: 4, Policy: QueueConflate})
+	defer q.close()
+
+	q.push([]byte("0"))
+	receive(t, delivered)
+	q.push([]byte("1"))
+	q.push([]byte("2"))
+	q.push(&queueTestEvent{N: 3})
+	if stats := q.Stats(); stats.Conflated != 0 || stats.Pending != 3 {
+		t.Fatalf("stats %+v, want nothing conflated and 3 pending", stats)
+	}
+	close(gate)
+}
+
+func TestDeliveryQueueBlock(t *testing.T) {
+	q, delivered, gate := gatedQueue(QueueConfig{Size: 1, Policy: QueueBlock})
+	defer q.close()
+
+	q.push(1)
+	receive(t, delivered)
+	q.push(2)
+
+	pushed := make(chan struct{})
+	go func() {
+		q.push(3)
+		close(pushed)
+	}()
+	select {
+	case <-pushed:
+		t.Fatal("push into a full blocking queue returned")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(gate)
+	select {
+	case <-pushed:
+	case <-time.After(time.Second):
+		t.Fatal("push still blocked once the queue drained")
+	}
+	for _, want := range []int{2, 3} {
+		if got := receive(t, delivered); got != want {
+			t.Fatalf("delivered %v, want %d", got, want)
+		}
+	}
+	if stats := q.Stats(); stats.Dropped != 0 || stats.Conflated != 0 {
+		t.Fatalf("blocking queue lost events: %+v", stats)
+	}
+}
+
+func TestDeliveryQueueClose(t *testing.T) {
+	q, delivered, gate := gatedQueue(QueueConfig{Size: 1, Policy: QueueBlock})
+
+	q.push(1)
+	receive(t, delivered)
+	q.push(2)
+	pushed := make(chan struct{})
+	go func() {
+		q.push(3)
+		close(pushed)
+	}()
+
+	q.close()
+	select {
+	case <-pushed:
+	case <-time.After(time.Second):
+		t.Fatal("close did not release a blocked push")
+	}
+	if stats := q.Stats(); stats.Pending != 0 {
+		t.Fatalf("closed queue still holds %d events", stats.Pending)
+	}
+
+	q.push(4)
+	close(gate)
+	select {
+	case got := <-delivered:
+		t.Fatalf("closed queue delivered %v", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,282 @@
+package okx
+
+import (
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"sync"
+
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/ws"
+)
+
+// ListenerPanicError is reported to the listener error hook when a listener
+// panics. The panic is recovered so the connection keeps reading.
+type ListenerPanicError struct {
+	Topic interface{}
+	Value interface{}
+	Stack []byte
+}
+
+func (e *ListenerPanicError) Error() string {
+	return fmt.Sprintf("listener of %v panicked: %v", e.Topic, e.Value)
+}
+
+// ListenerTypeError is reported when an event does not have the type a
+// listener accepts, e.g. the raw payload of a message that failed to decode.
+type ListenerTypeError struct {
+	Topic interface{}
+	Want  reflect.Type
+	Got   interface{}
+}
+
+func (e *ListenerTypeError) Error() string {
+	return fmt.Sprintf("listener of %v expects %v, got %T", e.Topic, e.Want, e.Got)
+}
+
+// Match is a wildcard topic for events keyed by ws.Args. It matches every
+// event whose arg has the same value in each field Match sets, so
+// Match{Channel: "tickers"} receives all tickers and Match{InstType: "SWAP"}
+// every event of SWAP instruments. InstType and InstFamily are also matched
+// against the first data item for channels whose arg only has an instId.
+type Match ws.Args
+
+func (m Match) matches(arg ws.Args, event interface{}) bool {
+	return matchField(m.Channel, arg.Channel, event, "") &&
+		matchField(m.InstId, arg.InstId, event, "InstId") &&
+		matchField(m.InstType, arg.InstType, event, "InstType") &&
+		matchField(m.InstFamily, arg.InstFamily, event, "InstFamily") &&
+		matchField(m.Uly, arg.Uly, event, "Uly")
+}
+
+func matchField(want, got string, event interface{}, dataField string) bool {
+	if want == "" || want == got {
+		return true
+	}
+	return got == "" && dataField != "" && eventDataField(event, dataField) == want
+}
+
+// Listener is the handle of a function registered on a WSClient. It is the
+// only way to remove the function again.
+type Listener struct {
+	id    uint64
+	topic interface{}
+	bus   *eventBus
+	fn    func(event interface{}) error
+	// queue is nil for listeners called on the publishing goroutine.
+	queue *deliveryQueue
+	// linked are the listeners registered together with this one on other
+	// clients, removed along with it.
+	linked []*Listener
+}
+
+func (l *Listener) Topic() interface{} {
+	return l.topic
+}
+
+// Remove unregisters the listener and discards events still queued for it.
+// Removing it twice is a no-op.
+func (l *Listener) Remove() {
+	l.bus.remove(l)
+	if l.queue != nil {
+		l.queue.close()
+	}
+	for _, other := range l.linked {
+		other.Remove()
+	}
+}
+
+// Stats reports the delivery counters of a queued listener, zero for others.
+func (l *Listener) Stats() QueueStats {
+	var stats QueueStats
+	if l.queue != nil {
+		stats = l.queue.Stats()
+	}
+	for _, other := range l.linked {
+		stats = stats.add(other.Stats())
+	}
+	return stats
+}
+
+// eventBus dispatches events to the listeners of their topic, a ws.Args or
+// a string such as "error", and to the Match listeners of ws.Args topics.
+// Listeners run on the publishing goroutine unless they are queued.
+type eventBus struct {
+	mu        sync.RWMutex
+	nextId    uint64
+	listeners map[interface{}][]*Listener
+	wildcards []*Listener
+	onError   func(topic interface{}, err error)
+	logger    loggerRef
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{listeners: make(map[interface{}][]*Listener)}
+}
+
+func (b *eventBus) add(topic interface{}, fn func(event interface{}) error) *Listener {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nextId++
+	l := &Listener{id: b.nextId, topic: topic, bus: b, fn: fn}
+	b.register(l)
+	return l
+}
+
+// addQueued registers fn behind a delivery queue, so it runs on a goroutine
+// of its own instead of the publishing one.
+func (b *eventBus) addQueued(topic interface{}, fn func(event interface{}) error, config QueueConfig) *Listener {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nextId++
+	l := &Listener{id: b.nextId, topic: topic, bus: b, fn: fn}
+	l.queue = newDeliveryQueue(config, func(event interface{}) {
+		b.call(l, event)
+	})
+	b.register(l)
+	return l
+}
+
+// register files l under its topic. The caller must hold mu.
+func (b *eventBus) register(l *Listener) {
+	if _, ok := l.topic.(Match); ok {
+		b.wildcards = append(b.wildcards, l)
+		return
+	}
+	b.listeners[l.topic] = append(b.listeners[l.topic], l)
+}
+
+func (b *eventBus) remove(l *Listener) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := l.topic.(Match); ok {
+		b.wildcards = removeListener(b.wildcards, l)
+		return
+	}
+	b.listeners[l.topic] = removeListener(b.listeners[l.topic], l)
+	if len(b.listeners[l.topic]) == 0 {
+		delete(b.listeners, l.topic)
+	}
+}
+
+// has reports whether topic has a listener of its own, not counting Match
+// listeners.
+func (b *eventBus) has(topic interface{}) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.listeners[topic]) > 0
+}
+
+// removeListener returns listeners without l, leaving the slice that
+// publish may be iterating untouched.
+func removeListener(listeners []*Listener, l *Listener) []*Listener {
+	for i, other := range listeners {
+		if other.id == l.id {
+			return append(listeners[:i:i], listeners[i+1:]...)
+		}
+	}
+	return listeners
+}
+
+func (b *eventBus) publish(topic interface{}, event interface{}) {
+	b.mu.RLock()
+	listeners := b.listeners[topic]
+	if arg, ok := topic.(ws.Args); ok && len(b.wildcards) > 0 {
+		listeners = append([]*Listener{}, listeners...)
+		for _, l := range b.wildcards {
+			if l.topic.(Match).matches(arg, event) {
+				listeners = append(listeners, l)
+			}
+		}
+	}
+	b.mu.RUnlock()
+	for _, l := range listeners {
+		if l.queue != nil {
+			l.queue.push(event)
+		} else {
+			b.call(l, event)
+		}
+	}
+}
+
+func (b *eventBus) call(l *Listener, event interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.report(l.topic, &ListenerPanicError{Topic: l.topic, Value: r, Stack: debug.Stack()})
+		}
+	}()
+	if err := l.fn(event); err != nil {
+		b.report(l.topic, err)
+	}
+}
+
+func (b *eventBus) setErrorHandler(fn func(topic interface{}, err error)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onError = fn
+}
+
+func (b *eventBus) report(topic interface{}, err error) {
+	b.mu.RLock()
+	onError := b.onError
+	b.mu.RUnlock()
+	if onError == nil {
+		b.logger.get().Error("event listener failed", "topic", fmt.Sprint(topic), "error", err)
+		return
+	}
+	onError(topic, err)
+}
+
+// typedListener adapts fn to the bus, rejecting events that are not a T.
+func typedListener[T any](topic interface{}, fn func(T)) func(event interface{}) error {
+	return func(event interface{}) error {
+		typed, ok := event.(T)
+		if !ok {
+			return &ListenerTypeError{Topic: topic, Want: reflect.TypeOf((*T)(nil)).Elem(), Got: event}
+		}
+		fn(typed)
+		return nil
+	}
+}
+
+// reflectListener adapts a func taking zero or one argument to the bus. Its
+// signature is checked once here rather than on every event.
+func reflectListener(topic interface{}, listener interface{}) (func(event interface{}) error, error) {
+	fn := reflect.ValueOf(listener)
+	if fn.Kind() != reflect.Func || fn.Type().NumIn() > 1 {
+		return nil, fmt.Errorf("listener of %v must be a func with at most one argument, got %T", topic, listener)
+	}
+	if fn.Type().NumIn() == 0 {
+		return func(interface{}) error {
+			fn.Call(nil)
+			return nil
+		}, nil
+	}
+
+	want := fn.Type().In(0)
+	return func(event interface{}) error {
+		var arg reflect.Value
+		if event == nil {
+			arg = reflect.Zero(want)
+		} else {
+			arg = reflect.ValueOf(event)
+			if !arg.Type().AssignableTo(want) {
+				return &ListenerTypeError{Topic: topic, Want: want, Got: event}
+			}
+		}
+		fn.Call([]reflect.Value{arg})
+		return nil
+	}, nil
+}
+
+// Listen registers fn for events of type T published on topic, e.g. a ws.Args
+// with *public.TickerEvent. Events of any other type are reported to the
+// listener error hook instead of reaching fn.
+func Listen[T any](client *WSClient, topic interface{}, fn func(T)) *Listener {
+	return client.bus.add(topic, typedListener(topic, fn))
+}
+
+// ListenQueued is Listen with a delivery queue, so a slow fn only delays its
+// own events as far as config's policy allows.
+func ListenQueued[T any](client *WSClient, topic interface{}, config QueueConfig, fn func(T)) *Listener {
+	return client.bus.addQueued(topic, typedListener(topic, fn), config)
+}
@@ -0,0 +1,58 @@
+package okx
+
+import (
+	"log/slog"
+	"sync/atomic"
+)
+
+// Logger receives the log records of the SDK. fields alternate keys and
+// values, e.g. "endpoint", "public", "channel", "tickers", as in log/slog.
+type Logger interface {
+	Debug(msg string, fields ...interface{})
+	Info(msg string, fields ...interface{})
+	Warn(msg string, fields ...interface{})
+	Error(msg string, fields ...interface{})
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...interface{}) {}
+func (nopLogger) Info(string, ...interface{})  {}
+func (nopLogger) Warn(string, ...interface{})  {}
+func (nopLogger) Error(string, ...interface{}) {}
+
+// NopLogger discards everything. It is the default of every client.
+func NopLogger() Logger {
+	return nopLogger{}
+}
+
+// NewSlogLogger logs through l, or slog.Default() if l is nil.
+func NewSlogLogger(l *slog.Logger) Logger {
+	if l == nil {
+		l = slog.Default()
+	}
+	return l
+}
+
+// loggerRef holds a Logger that can be replaced while goroutines log.
+type loggerRef struct {
+	v atomic.Value
+}
+
+type loggerBox struct {
+	Logger
+}
+
+func (r *loggerRef) set(l Logger) {
+	if l == nil {
+		l = NopLogger()
+	}
+	r.v.Store(loggerBox{l})
+}
+
+func (r *loggerRef) get() Logger {
+	if box, ok := r.v.Load().(loggerBox); ok {
+		return box.Logger
+	}
+	return NopLogger()
+}
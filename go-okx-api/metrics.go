@@ -0,0 +1,292 @@
+package okx
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/ws/public"
+)
+
+// Metrics receives measurements of REST and websocket activity. It is called
+// on the hot path, so implementations must be cheap and safe for concurrent
+// use.
+type Metrics interface {
+	// MessageReceived counts every push of a channel.
+	MessageReceived(endpoint, channel string)
+	DecodeFailed(endpoint, channel string)
+	Reconnected(endpoint string)
+	PingRTT(endpoint string, rtt time.Duration)
+	// RestRequest records a REST call. status is 0 if no response arrived.
+	RestRequest(method, path string, status int, latency time.Duration)
+	// ExchangeLatency is the time from the ts OKX stamped on a ticker, trade
+	// or order book to its receipt.
+	ExchangeLatency(channel string, latency time.Duration)
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) MessageReceived(string, string)                 {}
+func (nopMetrics) DecodeFailed(string, string)                    {}
+func (nopMetrics) Reconnected(string)                             {}
+func (nopMetrics) PingRTT(string, time.Duration)                  {}
+func (nopMetrics) RestRequest(string, string, int, time.Duration) {}
+func (nopMetrics) ExchangeLatency(string, time.Duration)          {}
+
+// NopMetrics discards every measurement. It is the default of every client.
+func NopMetrics() Metrics {
+	return nopMetrics{}
+}
+
+// metricsRef holds a Metrics that can be replaced while goroutines record.
+type metricsRef struct {
+	v atomic.Value
+}
+
+type metricsBox struct {
+	Metrics
+}
+
+func (r *metricsRef) set(m Metrics) {
+	if m == nil {
+		m = NopMetrics()
+	}
+	r.v.Store(metricsBox{m})
+}
+
+func (r *metricsRef) get() Metrics {
+	if box, ok := r.v.Load().(metricsBox); ok {
+		return box.Metrics
+	}
+	return NopMetrics()
+}
+
+// DefaultLatencyBuckets are the histogram bounds, in seconds, of every
+// duration InMemoryMetrics records.
+var DefaultLatencyBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+const (
+	metricMessages        = "okx_ws_messages_total"
+	metricDecodeFailures  = "okx_ws_decode_failures_total"
+	metricReconnects      = "okx_ws_reconnects_total"
+	metricPingRTT         = "okx_ws_ping_rtt_seconds"
+	metricExchangeLatency = "okx_ws_exchange_latency_seconds"
+	metricRestDuration    = "okx_rest_request_duration_seconds"
+)
+
+var metricHelp = map[string]string{
+	metricMessages:        "Websocket pushes received by endpoint and channel.",
+	metricDecodeFailures:  "Websocket pushes that failed to decode by endpoint and channel.",
+	metricReconnects:      "Successful websocket reconnects by endpoint.",
+	metricPingRTT:         "Round trip time of websocket pings.",
+	metricExchangeLatency: "Time from the exchange timestamp of a push to its receipt.",
+	metricRestDuration:    "Latency of REST requests by method, path and status.",
+}
+
+type metricKey struct {
+	name   string
+	labels string
+}
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// InMemoryMetrics keeps counters and histograms in memory and serves them in
+// the Prometheus text format.
+type InMemoryMetrics struct {
+	mu         sync.Mutex
+	counters   map[metricKey]float64
+	histograms map[metricKey]*histogram
+}
+
+func NewInMemoryMetrics() *InMemoryMetrics {
+	return &InMemoryMetrics{
+		counters:   make(map[metricKey]float64),
+		histograms: make(map[metricKey]*histogram),
+	}
+}
+
+func (m *InMemoryMetrics) MessageReceived(endpoint, channel string) {
+	m.add(metricMessages, labels("endpoint", endpoint, "channel", channel))
+}
+
+func (m *InMemoryMetrics) DecodeFailed(endpoint, channel string) {
+	m.add(metricDecodeFailures, labels("endpoint", endpoint, "channel", channel))
+}
+
+func (m *InMemoryMetrics) Reconnected(endpoint string) {
+	m.add(metricReconnects, labels("endpoint", endpoint))
+}
+
+func (m *InMemoryMetrics) PingRTT(endpoint string, rtt time.Duration) {
+	m.observe(metricPingRTT, labels("endpoint", endpoint), rtt)
+}
+
+func (m *InMemoryMetrics) RestRequest(method, path string, status int, latency time.Duration) {
+	m.observe(metricRestDuration, labels("method", method, "path", path, "status", strconv.Itoa(status)), latency)
+}
+
+func (m *InMemoryMetrics) ExchangeLatency(channel string, latency time.Duration) {
+	m.observe(metricExchangeLatency, labels("channel", channel), latency)
+}
+
+// Counter returns the value of a counter, e.g.
+// Counter("okx_ws_reconnects_total", "endpoint", "public").
+func (m *InMemoryMetrics) Counter(name string, labelPairs ...string) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.counters[metricKey{name, labels(labelPairs...)}]
+}
+
+func (m *InMemoryMetrics) add(name, labels string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counters[metricKey{name, labels}]++
+}
+
+func (m *InMemoryMetrics) observe(name, labels string, d time.Duration) {
+	seconds := d.Seconds()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := metricKey{name, labels}
+	h, ok := m.histograms[key]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(DefaultLatencyBuckets))}
+		m.histograms[key] = h
+	}
+	for i, bound := range DefaultLatencyBuckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// WritePrometheus writes every metric in the Prometheus text format.
+func (m *InMemoryMetrics) WritePrometheus(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for _, name := range sortedNames(m.counters) {
+		writeHeader(bw, name, "counter")
+		for _, key := range sortedKeys(m.counters, name) {
+			fmt.Fprintf(bw, "%s%s %s\n", name, braces(key.labels), formatFloat(m.counters[key]))
+		}
+	}
+	for _, name := range sortedNames(m.histograms) {
+		writeHeader(bw, name, "histogram")
+		for _, key := range sortedKeys(m.histograms, name) {
+			h := m.histograms[key]
+			for i, bound := range DefaultLatencyBuckets {
+				fmt.Fprintf(bw, "%s_bucket%s %d\n", name, braces(joinLabels(key.labels, labels("le", formatFloat(bound)))), h.counts[i])
+			}
+			fmt.Fprintf(bw, "%s_bucket%s %d\n", name, braces(joinLabels(key.labels, labels("le", "+Inf"))), h.count)
+			fmt.Fprintf(bw, "%s_sum%s %s\n", name, braces(key.labels), formatFloat(h.sum))
+			fmt.Fprintf(bw, "%s_count%s %d\n", name, braces(key.labels), h.count)
+		}
+	}
+	return bw.Flush()
+}
+
+// ServeHTTP exposes the metrics to a Prometheus scraper.
+func (m *InMemoryMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WritePrometheus(w)
+}
+
+func writeHeader(w io.Writer, name, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, metricHelp[name], name, kind)
+}
+
+// labels renders alternating names and values as Prometheus labels.
+func labels(pairs ...string) string {
+	var b strings.Builder
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if b.Len() > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(pairs[i])
+		b.WriteString("=")
+		b.WriteString(strconv.Quote(pairs[i+1]))
+	}
+	return b.String()
+}
+
+func joinLabels(a, b string) string {
+	if a == "" {
+		return b
+	}
+	return a + "," + b
+}
+
+func braces(labels string) string {
+	if labels == "" {
+		return ""
+	}
+	return "{" + labels + "}"
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func sortedNames[V any](metrics map[metricKey]V) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for key := range metrics {
+		if !seen[key.name] {
+			seen[key.name] = true
+			names = append(names, key.name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedKeys[V any](metrics map[metricKey]V, name string) []metricKey {
+	var keys []metricKey
+	for key := range metrics {
+		if key.name == name {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].labels < keys[j].labels })
+	return keys
+}
+
+// recordExchangeLatency reports the delay between OKX's timestamp of every
+// item of a ticker, trade or order book event and now.
+func recordExchangeLatency(metrics Metrics, channel string, event interface{}, received time.Time) {
+	record := func(ms int64) {
+		if ms > 0 {
+			metrics.ExchangeLatency(channel, received.Sub(time.Unix(0, ms*int64(time.Millisecond))))
+		}
+	}
+	switch e := event.(type) {
+	case *public.TickerEvent:
+		for _, ticker := range e.Data {
+			record(ticker.Ts)
+		}
+	case *public.TradeEvent:
+		for _, trade := range e.Data {
+			record(trade.Ts)
+		}
+	case *public.OrderBookEvent:
+		for _, book := range e.Data {
+			ms, _ := strconv.ParseInt(book.Timestamp, 10, 64)
+			record(ms)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package okx
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/rest/account"
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/rest/trade"
+)
+
+// MassCancel cancels every MMP pending option order of instFamily and blocks
+// new orders for lockInterval.
+func (c *RestClient) MassCancel(instFamily string, lockInterval time.Duration) error {
+	param := &trade.MassCancelParam{
+		InstType:   "OPTION",
+		InstFamily: instFamily,
+	}
+	if lockInterval > 0 {
+		param.LockInterval = strconv.FormatInt(int64(lockInterval/time.Millisecond), 10)
+	}
+
+	req, resp := trade.NewMassCancel(param)
+	if err := c.Do(req, resp); err != nil {
+		return err
+	}
+	if data := resp.(*trade.MassCancelResponse).Data; len(data) == 0 || !data[0].Result {
+		return errors.New("mass cancel rejected for " + instFamily)
+	}
+	return nil
+}
+
+// SetMmpConfig configures market maker protection for instFamily. A zero
+// timeInterval disables MMP, a zero frozenInterval keeps it frozen until reset.
+func (c *RestClient) SetMmpConfig(instFamily string, timeInterval, frozenInterval time.Duration, qtyLimit string) error {
+	req, resp := account.NewSetMmpConfig(&account.SetMmpConfigParam{
+		InstFamily:     instFamily,
+		TimeInterval:   strconv.FormatInt(int64(timeInterval/time.Millisecond), 10),
+		FrozenInterval: strconv.FormatInt(int64(frozenInterval/time.Millisecond), 10),
+		QtyLimit:       qtyLimit,
+	})
+	return c.Do(req, resp)
+}
+
+// ResetMmp unfreezes instFamily after market maker protection was triggered.
+func (c *RestClient) ResetMmp(instFamily string) error {
+	req, resp := account.NewMmpReset(&account.MmpResetParam{
+		InstType:   "OPTION",
+		InstFamily: instFamily,
+	})
+	if err := c.Do(req, resp); err != nil {
+		return err
+	}
+	if data := resp.(*account.MmpResetResponse).Data; len(data) == 0 || !data[0].Result {
+		return errors.New("mmp reset rejected for " + instFamily)
+	}
+	return nil
+}
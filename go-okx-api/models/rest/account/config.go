@@ -0,0 +1,41 @@
+package account
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+const (
+	PosModeNet       = "net_mode"
+	PosModeLongShort = "long_short_mode"
+)
+
+func NewGetConfig() (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/account/config",
+		Method: rest.MethodGet,
+	}, &GetConfigResponse{}
+}
+
+type GetConfigResponse struct {
+	rest.Response
+	Data []Config `json:"data"`
+}
+
+type Config struct {
+	Uid            string   `json:"uid"`
+	MainUid        string   `json:"mainUid"`
+	AcctLv         string   `json:"acctLv"`
+	PosMode        string   `json:"posMode"`
+	AutoLoan       bool     `json:"autoLoan"`
+	GreeksType     string   `json:"greeksType"`
+	Level          string   `json:"level"`
+	LevelTmp       string   `json:"levelTmp"`
+	CtIsoMode      string   `json:"ctIsoMode"`
+	MgnIsoMode     string   `json:"mgnIsoMode"`
+	SpotOffsetType string   `json:"spotOffsetType"`
+	RoleType       string   `json:"roleType"`
+	TraderInsts    []string `json:"traderInsts"`
+	OpAuth         string   `json:"opAuth"`
+	KycLv          string   `json:"kycLv"`
+	Label          string   `json:"label"`
+	Ip             string   `json:"ip"`
+	Perm           string   `json:"perm"`
+}
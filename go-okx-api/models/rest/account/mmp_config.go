@@ -0,0 +1,49 @@
+package account
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+func NewGetMmpConfig(param *GetMmpConfigParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/account/mmp-config",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &GetMmpConfigResponse{}
+}
+
+func NewSetMmpConfig(param *SetMmpConfigParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/account/mmp-config",
+		Method: rest.MethodPost,
+		Param:  param,
+	}, &SetMmpConfigResponse{}
+}
+
+type GetMmpConfigParam struct {
+	InstFamily string `url:"instFamily,omitempty"` // Instrument family, e.g. BTC-USD
+}
+
+type GetMmpConfigResponse struct {
+	rest.Response
+	Data []MmpConfig `json:"data"`
+}
+
+type MmpConfig struct {
+	InstFamily     string `json:"instFamily"`
+	MmpFrozen      bool   `json:"mmpFrozen"`
+	MmpFrozenUntil string `json:"mmpFrozenUntil"`
+	TimeInterval   string `json:"timeInterval"`
+	FrozenInterval string `json:"frozenInterval"`
+	QtyLimit       string `json:"qtyLimit"`
+}
+
+type SetMmpConfigParam struct {
+	InstFamily     string `json:"instFamily"`     // Instrument family, e.g. BTC-USD
+	TimeInterval   string `json:"timeInterval"`   // Time window in milliseconds, 0 disables MMP
+	FrozenInterval string `json:"frozenInterval"` // Frozen period in milliseconds, 0 freezes until reset
+	QtyLimit       string `json:"qtyLimit"`       // Trade qty limit in number of contracts
+}
+
+type SetMmpConfigResponse struct {
+	rest.Response
+	Data []SetMmpConfigParam `json:"data"`
+}
@@ -0,0 +1,26 @@
+package account
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+// NewMmpReset unfreezes an instrument family after MMP was triggered.
+func NewMmpReset(param *MmpResetParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/account/mmp-reset",
+		Method: rest.MethodPost,
+		Param:  param,
+	}, &MmpResetResponse{}
+}
+
+type MmpResetParam struct {
+	InstType   string `json:"instType,omitempty"` // Instrument type, OPTION
+	InstFamily string `json:"instFamily"`         // Instrument family, e.g. BTC-USD
+}
+
+type MmpResetResponse struct {
+	rest.Response
+	Data []MmpReset `json:"data"`
+}
+
+type MmpReset struct {
+	Result bool `json:"result"`
+}
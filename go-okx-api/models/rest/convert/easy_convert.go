@@ -0,0 +1,72 @@
+package convert
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+func NewGetEasyConvertCurrencyList(param *GetEasyConvertCurrencyListParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/trade/easy-convert-currency-list",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &GetEasyConvertCurrencyListResponse{}
+}
+
+// NewEasyConvert converts small balances of up to 5 currencies into toCcy.
+func NewEasyConvert(param *EasyConvertParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/trade/easy-convert",
+		Method: rest.MethodPost,
+		Param:  param,
+	}, &EasyConvertResponse{}
+}
+
+func NewGetEasyConvertHistory(param *GetEasyConvertHistoryParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/trade/easy-convert-history",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &EasyConvertResponse{}
+}
+
+type GetEasyConvertCurrencyListParam struct {
+	Source string `url:"source,omitempty"` // Funding source, 1: trading account, 2: funding account
+}
+
+type GetEasyConvertCurrencyListResponse struct {
+	rest.Response
+	Data []EasyConvertCurrencyList `json:"data"`
+}
+
+type EasyConvertCurrencyList struct {
+	FromData []struct {
+		FromCcy string `json:"fromCcy"`
+		FromAmt string `json:"fromAmt"`
+	} `json:"fromData"`
+	ToCcy []string `json:"toCcy"`
+}
+
+type EasyConvertParam struct {
+	FromCcy []string `json:"fromCcy"`          // Currencies to convert from, maximum 5
+	ToCcy   string   `json:"toCcy"`            // Currency to convert to
+	Source  string   `json:"source,omitempty"` // Funding source, 1: trading account, 2: funding account
+}
+
+type GetEasyConvertHistoryParam struct {
+	After  string `url:"after,omitempty"`  // Pagination of data to return records earlier than the requested ts
+	Before string `url:"before,omitempty"` // Pagination of data to return records newer than the requested ts
+	Limit  string `url:"limit,omitempty"`  // Number of results per request, maximum 100
+}
+
+type EasyConvertResponse struct {
+	rest.Response
+	Data []EasyConvert `json:"data"`
+}
+
+type EasyConvert struct {
+	FromCcy    string `json:"fromCcy"`
+	FillFromSz string `json:"fillFromSz"`
+	ToCcy      string `json:"toCcy"`
+	FillToSz   string `json:"fillToSz"`
+	Acct       string `json:"acct"`
+	Status     string `json:"status"`
+	UTime      string `json:"uTime"`
+}
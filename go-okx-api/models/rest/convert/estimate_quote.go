@@ -0,0 +1,96 @@
+package convert
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+)
+
+var ErrQuoteExpired = errors.New("convert quote expired")
+
+func NewEstimateQuote(param *EstimateQuoteParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/asset/convert/estimate-quote",
+		Method: rest.MethodPost,
+		Param:  param,
+	}, &EstimateQuoteResponse{}
+}
+
+type EstimateQuoteParam struct {
+	BaseCcy  string `json:"baseCcy"`            // Base currency, e.g. BTC in BTC-USDT
+	QuoteCcy string `json:"quoteCcy"`           // Quote currency, e.g. USDT in BTC-USDT
+	Side     string `json:"side"`               // Trade side based on baseCcy, buy or sell
+	RfqSz    string `json:"rfqSz"`              // RFQ amount
+	RfqSzCcy string `json:"rfqSzCcy"`           // RFQ currency
+	ClQReqId string `json:"clQReqId,omitempty"` // Client Order ID as assigned by the client
+	Tag      string `json:"tag,omitempty"`      // Order tag
+}
+
+type EstimateQuoteResponse struct {
+	rest.Response
+	Data []Quote `json:"data"`
+}
+
+type Quote struct {
+	QuoteTime string `json:"quoteTime"`
+	TtlMs     string `json:"ttlMs"`
+	ClQReqId  string `json:"clQReqId"`
+	QuoteId   string `json:"quoteId"`
+	BaseCcy   string `json:"baseCcy"`
+	QuoteCcy  string `json:"quoteCcy"`
+	Side      string `json:"side"`
+	OrigRfqSz string `json:"origRfqSz"`
+	RfqSz     string `json:"rfqSz"`
+	RfqSzCcy  string `json:"rfqSzCcy"`
+	CnvtPx    string `json:"cnvtPx"`
+	BaseSz    string `json:"baseSz"`
+	QuoteSz   string `json:"quoteSz"`
+}
+
+// ExpiresAt returns the time after which OKX no longer accepts the quote.
+func (q Quote) ExpiresAt() (time.Time, error) {
+	quoteTime, err := strconv.ParseInt(q.QuoteTime, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid quoteTime %q: %v", q.QuoteTime, err)
+	}
+	ttl, err := strconv.ParseInt(q.TtlMs, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid ttlMs %q: %v", q.TtlMs, err)
+	}
+	return time.UnixMilli(quoteTime + ttl), nil
+}
+
+func (q Quote) IsExpired(now time.Time) (bool, error) {
+	expiresAt, err := q.ExpiresAt()
+	if err != nil {
+		return false, err
+	}
+	return !now.Before(expiresAt), nil
+}
+
+// TradeParam builds the parameters to execute the quote in full. It returns
+// ErrQuoteExpired when the quote can no longer be traded.
+func (q Quote) TradeParam(clTReqId string) (*TradeParam, error) {
+	expired, err := q.IsExpired(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if expired {
+		return nil, ErrQuoteExpired
+	}
+	return &TradeParam{
+		QuoteId:  q.QuoteId,
+		BaseCcy:  q.BaseCcy,
+		QuoteCcy: q.QuoteCcy,
+		Side:     q.Side,
+		Sz:       q.RfqSz,
+		SzCcy:    q.RfqSzCcy,
+		ClTReqId: clTReqId,
+	}, nil
+}
+
+func (p *EstimateQuoteParam) GetTag() string    { return p.Tag }
+func (p *EstimateQuoteParam) SetTag(tag string) { p.Tag = tag }
@@ -0,0 +1,49 @@
+package convert
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+func NewGetCurrencies() (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/asset/convert/currencies",
+		Method: rest.MethodGet,
+	}, &GetCurrenciesResponse{}
+}
+
+func NewGetCurrencyPair(param *GetCurrencyPairParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/asset/convert/currency-pair",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &GetCurrencyPairResponse{}
+}
+
+type GetCurrenciesResponse struct {
+	rest.Response
+	Data []Currency `json:"data"`
+}
+
+type Currency struct {
+	Ccy string `json:"ccy"`
+	Min string `json:"min"`
+	Max string `json:"max"`
+}
+
+type GetCurrencyPairParam struct {
+	FromCcy string `url:"fromCcy"` // Currency to convert from, e.g. USDT
+	ToCcy   string `url:"toCcy"`   // Currency to convert to, e.g. BTC
+}
+
+type GetCurrencyPairResponse struct {
+	rest.Response
+	Data []CurrencyPair `json:"data"`
+}
+
+type CurrencyPair struct {
+	InstId      string `json:"instId"`
+	BaseCcy     string `json:"baseCcy"`
+	BaseCcyMax  string `json:"baseCcyMax"`
+	BaseCcyMin  string `json:"baseCcyMin"`
+	QuoteCcy    string `json:"quoteCcy"`
+	QuoteCcyMax string `json:"quoteCcyMax"`
+	QuoteCcyMin string `json:"quoteCcyMin"`
+}
@@ -0,0 +1,67 @@
+package convert
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+func NewGetOneClickRepayCurrencyList(param *GetOneClickRepayCurrencyListParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/trade/one-click-repay-currency-list",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &GetOneClickRepayCurrencyListResponse{}
+}
+
+func NewOneClickRepay(param *OneClickRepayParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/trade/one-click-repay",
+		Method: rest.MethodPost,
+		Param:  param,
+	}, &OneClickRepayResponse{}
+}
+
+func NewGetOneClickRepayHistory(param *GetEasyConvertHistoryParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/trade/one-click-repay-history",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &OneClickRepayResponse{}
+}
+
+type GetOneClickRepayCurrencyListParam struct {
+	DebtType string `url:"debtType,omitempty"` // Debt type, cross or isolated
+}
+
+type GetOneClickRepayCurrencyListResponse struct {
+	rest.Response
+	Data []OneClickRepayCurrencyList `json:"data"`
+}
+
+type OneClickRepayCurrencyList struct {
+	DebtData []struct {
+		DebtCcy string `json:"debtCcy"`
+		DebtAmt string `json:"debtAmt"`
+	} `json:"debtData"`
+	DebtType  string `json:"debtType"`
+	RepayData []struct {
+		RepayCcy string `json:"repayCcy"`
+		RepayAmt string `json:"repayAmt"`
+	} `json:"repayData"`
+}
+
+type OneClickRepayParam struct {
+	DebtCcy  []string `json:"debtCcy"`  // Debt currencies, maximum 5
+	RepayCcy string   `json:"repayCcy"` // Repay currency
+}
+
+type OneClickRepayResponse struct {
+	rest.Response
+	Data []OneClickRepay `json:"data"`
+}
+
+type OneClickRepay struct {
+	DebtCcy     string `json:"debtCcy"`
+	FillDebtSz  string `json:"fillDebtSz"`
+	RepayCcy    string `json:"repayCcy"`
+	FillRepaySz string `json:"fillRepaySz"`
+	Status      string `json:"status"`
+	UTime       string `json:"uTime"`
+}
@@ -0,0 +1,63 @@
+package convert
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+func NewTrade(param *TradeParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/asset/convert/trade",
+		Method: rest.MethodPost,
+		Param:  param,
+	}, &TradeResponse{}
+}
+
+func NewGetHistory(param *GetHistoryParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/asset/convert/history",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &TradeResponse{}
+}
+
+type TradeParam struct {
+	QuoteId  string `json:"quoteId"`            // Quote ID returned by the estimate quote
+	BaseCcy  string `json:"baseCcy"`            // Base currency, e.g. BTC in BTC-USDT
+	QuoteCcy string `json:"quoteCcy"`           // Quote currency, e.g. USDT in BTC-USDT
+	Side     string `json:"side"`               // Trade side based on baseCcy, buy or sell
+	Sz       string `json:"sz"`                 // Quote amount, must not exceed the RFQ amount
+	SzCcy    string `json:"szCcy"`              // Quote currency
+	ClTReqId string `json:"clTReqId,omitempty"` // Client Order ID as assigned by the client
+	Tag      string `json:"tag,omitempty"`      // Order tag
+}
+
+type GetHistoryParam struct {
+	ClTReqId string `url:"clTReqId,omitempty"` // Client Order ID as assigned by the client
+	After    string `url:"after,omitempty"`    // Pagination of data to return records earlier than the requested ts
+	Before   string `url:"before,omitempty"`   // Pagination of data to return records newer than the requested ts
+	Limit    string `url:"limit,omitempty"`    // Number of results per request, maximum 100
+	Tag      string `url:"tag,omitempty"`      // Order tag
+}
+
+type TradeResponse struct {
+	rest.Response
+	Data []Trade `json:"data"`
+}
+
+type Trade struct {
+	TradeId     string `json:"tradeId"`
+	QuoteId     string `json:"quoteId"`
+	ClTReqId    string `json:"clTReqId"`
+	State       string `json:"state"`
+	InstId      string `json:"instId"`
+	BaseCcy     string `json:"baseCcy"`
+	QuoteCcy    string `json:"quoteCcy"`
+	Side        string `json:"side"`
+	FillPx      string `json:"fillPx"`
+	FillBaseSz  string `json:"fillBaseSz"`
+	FillQuoteSz string `json:"fillQuoteSz"`
+	Ts          string `json:"ts"`
+}
+
+func (p *TradeParam) GetClOrdId() string        { return p.ClTReqId }
+func (p *TradeParam) SetClOrdId(clOrdId string) { p.ClTReqId = clOrdId }
+func (p *TradeParam) GetTag() string            { return p.Tag }
+func (p *TradeParam) SetTag(tag string)         { p.Tag = tag }
@@ -0,0 +1,28 @@
+package copytrading
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+// NewPlaceAlgoOrder sets the take-profit and stop-loss of a lead position.
+func NewPlaceAlgoOrder(param *PlaceAlgoOrderParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/copytrading/algo-order",
+		Method: rest.MethodPost,
+		Param:  param,
+	}, &SubpositionResponse{}
+}
+
+type PlaceAlgoOrderParam struct {
+	InstType        string `json:"instType,omitempty"`        // SPOT or SWAP, default SWAP
+	SubPosId        string `json:"subPosId"`                  // Lead position ID
+	TpTriggerPx     string `json:"tpTriggerPx,omitempty"`     // Take-profit trigger price
+	SlTriggerPx     string `json:"slTriggerPx,omitempty"`     // Stop-loss trigger price
+	TpOrdPx         string `json:"tpOrdPx,omitempty"`         // Take-profit order price, -1 for market price
+	SlOrdPx         string `json:"slOrdPx,omitempty"`         // Stop-loss order price, -1 for market price
+	TpTriggerPxType string `json:"tpTriggerPxType,omitempty"` // last, index or mark
+	SlTriggerPxType string `json:"slTriggerPxType,omitempty"` // last, index or mark
+	Tag             string `json:"tag,omitempty"`             // Order tag
+	SubPosType      string `json:"subPosType,omitempty"`      // lead or copy, default lead
+}
+
+func (p *PlaceAlgoOrderParam) GetTag() string    { return p.Tag }
+func (p *PlaceAlgoOrderParam) SetTag(tag string) { p.Tag = tag }
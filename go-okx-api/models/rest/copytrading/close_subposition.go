@@ -0,0 +1,32 @@
+package copytrading
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+func NewCloseSubposition(param *CloseSubpositionParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/copytrading/close-subposition",
+		Method: rest.MethodPost,
+		Param:  param,
+	}, &SubpositionResponse{}
+}
+
+type CloseSubpositionParam struct {
+	InstType string `json:"instType,omitempty"` // SPOT or SWAP, default SWAP
+	SubPosId string `json:"subPosId"`           // Lead position ID
+	OrdType  string `json:"ordType,omitempty"`  // market or limit, default market
+	Px       string `json:"px,omitempty"`       // Order price, only applicable to limit order
+	Tag      string `json:"tag,omitempty"`      // Order tag
+}
+
+func (p *CloseSubpositionParam) GetTag() string    { return p.Tag }
+func (p *CloseSubpositionParam) SetTag(tag string) { p.Tag = tag }
+
+type SubpositionResponse struct {
+	rest.Response
+	Data []SubpositionResult `json:"data"`
+}
+
+type SubpositionResult struct {
+	SubPosId string `json:"subPosId"`
+	Tag      string `json:"tag"`
+}
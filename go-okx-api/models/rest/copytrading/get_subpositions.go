@@ -0,0 +1,62 @@
+package copytrading
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+func NewGetCurrentSubpositions(param *GetSubpositionsParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/copytrading/current-subpositions",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &GetSubpositionsResponse{}
+}
+
+func NewGetSubpositionsHistory(param *GetSubpositionsParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/copytrading/subpositions-history",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &GetSubpositionsResponse{}
+}
+
+type GetSubpositionsParam struct {
+	InstType string `url:"instType,omitempty"` // SPOT or SWAP, default SWAP
+	InstId   string `url:"instId,omitempty"`   // Instrument ID, e.g. BTC-USDT-SWAP
+	After    string `url:"after,omitempty"`    // Pagination of data to return records earlier than the requested subPosId
+	Before   string `url:"before,omitempty"`   // Pagination of data to return records newer than the requested subPosId
+	Limit    string `url:"limit,omitempty"`    // Number of results per request, maximum 500
+}
+
+type GetSubpositionsResponse struct {
+	rest.Response
+	Data []Subposition `json:"data"`
+}
+
+type Subposition struct {
+	InstId           string `json:"instId"`
+	SubPosId         string `json:"subPosId"`
+	PosSide          string `json:"posSide"`
+	MgnMode          string `json:"mgnMode"`
+	Lever            string `json:"lever"`
+	OpenOrdId        string `json:"openOrdId"`
+	OpenAvgPx        string `json:"openAvgPx"`
+	OpenTime         string `json:"openTime"`
+	SubPos           string `json:"subPos"`
+	TpTriggerPx      string `json:"tpTriggerPx"`
+	SlTriggerPx      string `json:"slTriggerPx"`
+	AlgoId           string `json:"algoId"`
+	InstType         string `json:"instType"`
+	TpOrdPx          string `json:"tpOrdPx"`
+	SlOrdPx          string `json:"slOrdPx"`
+	Margin           string `json:"margin"`
+	Upl              string `json:"upl"`
+	UplRatio         string `json:"uplRatio"`
+	MarkPx           string `json:"markPx"`
+	UniqueCode       string `json:"uniqueCode"`
+	Ccy              string `json:"ccy"`
+	CloseAvgPx       string `json:"closeAvgPx"`
+	CloseTime        string `json:"closeTime"`
+	Pnl              string `json:"pnl"`
+	PnlRatio         string `json:"pnlRatio"`
+	ProfitSharingAmt string `json:"profitSharingAmt"`
+	Type             string `json:"type"`
+}
@@ -0,0 +1,39 @@
+package copytrading
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+func NewGetInstruments(param *GetInstrumentsParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/copytrading/instruments",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &InstrumentsResponse{}
+}
+
+// NewSetInstruments replaces the set of instruments the lead trader leads on.
+func NewSetInstruments(param *SetInstrumentsParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/copytrading/set-instruments",
+		Method: rest.MethodPost,
+		Param:  param,
+	}, &InstrumentsResponse{}
+}
+
+type GetInstrumentsParam struct {
+	InstType string `url:"instType,omitempty"` // SPOT or SWAP, default SWAP
+}
+
+type SetInstrumentsParam struct {
+	InstType string `json:"instType,omitempty"` // SPOT or SWAP, default SWAP
+	InstId   string `json:"instId"`             // Comma separated instrument IDs, e.g. BTC-USDT-SWAP,ETH-USDT-SWAP
+}
+
+type InstrumentsResponse struct {
+	rest.Response
+	Data []LeadInstrument `json:"data"`
+}
+
+type LeadInstrument struct {
+	InstId  string `json:"instId"`
+	Enabled bool   `json:"enabled"`
+}
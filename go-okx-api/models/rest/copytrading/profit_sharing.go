@@ -0,0 +1,78 @@
+package copytrading
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+func NewGetProfitSharingDetails(param *GetProfitSharingDetailsParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/copytrading/profit-sharing-details",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &GetProfitSharingDetailsResponse{}
+}
+
+func NewGetTotalProfitSharing(param *GetTotalProfitSharingParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/copytrading/total-profit-sharing",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &GetTotalProfitSharingResponse{}
+}
+
+func NewGetUnrealizedProfitSharingDetails(param *GetTotalProfitSharingParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/copytrading/unrealized-profit-sharing-details",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &GetUnrealizedProfitSharingDetailsResponse{}
+}
+
+type GetProfitSharingDetailsParam struct {
+	InstType string `url:"instType,omitempty"` // SPOT or SWAP, default SWAP
+	After    string `url:"after,omitempty"`    // Pagination of data to return records earlier than the requested profitSharingId
+	Before   string `url:"before,omitempty"`   // Pagination of data to return records newer than the requested profitSharingId
+	Limit    string `url:"limit,omitempty"`    // Number of results per request, maximum 100
+}
+
+type GetProfitSharingDetailsResponse struct {
+	rest.Response
+	Data []ProfitSharingDetail `json:"data"`
+}
+
+type ProfitSharingDetail struct {
+	Ccy              string `json:"ccy"`
+	ProfitSharingAmt string `json:"profitSharingAmt"`
+	NickName         string `json:"nickName"`
+	ProfitSharingId  string `json:"profitSharingId"`
+	InstType         string `json:"instType"`
+	PortLink         string `json:"portLink"`
+	Ts               string `json:"ts"`
+}
+
+type GetTotalProfitSharingParam struct {
+	InstType string `url:"instType,omitempty"` // SPOT or SWAP, default SWAP
+}
+
+type GetTotalProfitSharingResponse struct {
+	rest.Response
+	Data []TotalProfitSharing `json:"data"`
+}
+
+type TotalProfitSharing struct {
+	Ccy                   string `json:"ccy"`
+	TotalProfitSharingAmt string `json:"totalProfitSharingAmt"`
+	InstType              string `json:"instType"`
+}
+
+type GetUnrealizedProfitSharingDetailsResponse struct {
+	rest.Response
+	Data []UnrealizedProfitSharingDetail `json:"data"`
+}
+
+type UnrealizedProfitSharingDetail struct {
+	Ccy                        string `json:"ccy"`
+	UnrealizedProfitSharingAmt string `json:"unrealizedProfitSharingAmt"`
+	NickName                   string `json:"nickName"`
+	PortLink                   string `json:"portLink"`
+	Ts                         string `json:"ts"`
+	InstType                   string `json:"instType"`
+}
@@ -0,0 +1,30 @@
+package savings
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+func NewGetBalance(param *GetBalanceParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/finance/savings/balance",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &GetBalanceResponse{}
+}
+
+type GetBalanceParam struct {
+	Ccy string `url:"ccy,omitempty"` // Currency, e.g. BTC
+}
+
+type GetBalanceResponse struct {
+	rest.Response
+	Data []Balance `json:"data"`
+}
+
+type Balance struct {
+	Ccy        string `json:"ccy"`
+	Amt        string `json:"amt"`
+	Earnings   string `json:"earnings"`
+	Rate       string `json:"rate"`
+	LoanAmt    string `json:"loanAmt"`
+	PendingAmt string `json:"pendingAmt"`
+	RedemptAmt string `json:"redemptAmt"`
+}
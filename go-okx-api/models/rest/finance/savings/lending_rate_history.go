@@ -0,0 +1,53 @@
+package savings
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+// NewGetLendingRateHistory is a public endpoint and does not require
+// authentication.
+func NewGetLendingRateHistory(param *GetLendingRateHistoryParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/finance/savings/lending-rate-history",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &GetLendingRateHistoryResponse{}
+}
+
+func NewGetLendingHistory(param *GetLendingRateHistoryParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/finance/savings/lending-history",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &GetLendingHistoryResponse{}
+}
+
+type GetLendingRateHistoryParam struct {
+	Ccy    string `url:"ccy,omitempty"`    // Currency, e.g. BTC
+	After  string `url:"after,omitempty"`  // Pagination of data to return records earlier than the requested ts
+	Before string `url:"before,omitempty"` // Pagination of data to return records newer than the requested ts
+	Limit  string `url:"limit,omitempty"`  // Number of results per request, maximum 100
+}
+
+type GetLendingRateHistoryResponse struct {
+	rest.Response
+	Data []LendingRate `json:"data"`
+}
+
+type LendingRate struct {
+	Ccy  string `json:"ccy"`
+	Amt  string `json:"amt"`
+	Rate string `json:"rate"`
+	Ts   string `json:"ts"`
+}
+
+type GetLendingHistoryResponse struct {
+	rest.Response
+	Data []Lending `json:"data"`
+}
+
+type Lending struct {
+	Ccy      string `json:"ccy"`
+	Amt      string `json:"amt"`
+	Earnings string `json:"earnings"`
+	Rate     string `json:"rate"`
+	Ts       string `json:"ts"`
+}
@@ -0,0 +1,53 @@
+package savings
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+const (
+	SidePurchase = "purchase"
+	SideRedempt  = "redempt"
+)
+
+func NewPurchaseRedempt(param *PurchaseRedemptParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/finance/savings/purchase-redempt",
+		Method: rest.MethodPost,
+		Param:  param,
+	}, &PurchaseRedemptResponse{}
+}
+
+func NewSetLendingRate(param *SetLendingRateParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/finance/savings/set-lending-rate",
+		Method: rest.MethodPost,
+		Param:  param,
+	}, &SetLendingRateResponse{}
+}
+
+type PurchaseRedemptParam struct {
+	Ccy  string `json:"ccy"`            // Currency, e.g. BTC
+	Amt  string `json:"amt"`            // Purchase or redemption amount
+	Side string `json:"side"`           // SidePurchase or SideRedempt
+	Rate string `json:"rate,omitempty"` // Annual purchase rate, only applicable to purchase
+}
+
+type PurchaseRedemptResponse struct {
+	rest.Response
+	Data []PurchaseRedempt `json:"data"`
+}
+
+type PurchaseRedempt struct {
+	Ccy  string `json:"ccy"`
+	Amt  string `json:"amt"`
+	Side string `json:"side"`
+	Rate string `json:"rate"`
+}
+
+type SetLendingRateParam struct {
+	Ccy  string `json:"ccy"`  // Currency, e.g. BTC
+	Rate string `json:"rate"` // Annual lending rate
+}
+
+type SetLendingRateResponse struct {
+	rest.Response
+	Data []SetLendingRateParam `json:"data"`
+}
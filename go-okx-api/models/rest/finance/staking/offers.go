@@ -0,0 +1,52 @@
+package staking
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+const (
+	ProtocolTypeStaking = "staking"
+	ProtocolTypeDefi    = "defi"
+)
+
+func NewGetOffers(param *GetOffersParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/finance/staking-defi/offers",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &GetOffersResponse{}
+}
+
+type GetOffersParam struct {
+	ProductId    string `url:"productId,omitempty"`    // Product ID
+	ProtocolType string `url:"protocolType,omitempty"` // ProtocolTypeStaking or ProtocolTypeDefi
+	Ccy          string `url:"ccy,omitempty"`          // Investment currency, e.g. BTC
+}
+
+type GetOffersResponse struct {
+	rest.Response
+	Data []Offer `json:"data"`
+}
+
+type Offer struct {
+	Ccy          string        `json:"ccy"`
+	ProductId    string        `json:"productId"`
+	Protocol     string        `json:"protocol"`
+	ProtocolType string        `json:"protocolType"`
+	Term         string        `json:"term"`
+	Apy          string        `json:"apy"`
+	EarlyRedeem  bool          `json:"earlyRedeem"`
+	State        string        `json:"state"`
+	InvestData   []InvestLimit `json:"investData"`
+	EarningData  []struct {
+		Ccy         string `json:"ccy"`
+		EarningType string `json:"earningType"`
+	} `json:"earningData"`
+	RedeemPeriod             []string `json:"redeemPeriod"`
+	FastRedemptionDailyLimit string   `json:"fastRedemptionDailyLimit"`
+}
+
+type InvestLimit struct {
+	Bal    string `json:"bal"`
+	Ccy    string `json:"ccy"`
+	MaxAmt string `json:"maxAmt"`
+	MinAmt string `json:"minAmt"`
+}
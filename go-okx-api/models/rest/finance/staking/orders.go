@@ -0,0 +1,62 @@
+package staking
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+func NewGetActiveOrders(param *GetActiveOrdersParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/finance/staking-defi/orders-active",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &GetOrdersResponse{}
+}
+
+func NewGetOrdersHistory(param *GetOrdersHistoryParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/finance/staking-defi/orders-history",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &GetOrdersResponse{}
+}
+
+type GetActiveOrdersParam struct {
+	ProductId    string `url:"productId,omitempty"`    // Product ID
+	ProtocolType string `url:"protocolType,omitempty"` // ProtocolTypeStaking or ProtocolTypeDefi
+	Ccy          string `url:"ccy,omitempty"`          // Investment currency, e.g. BTC
+	State        string `url:"state,omitempty"`        // 8: pending, 13: cancelling, 9: onchain, 1: earning, 2: redeeming
+}
+
+type GetOrdersHistoryParam struct {
+	ProductId    string `url:"productId,omitempty"`    // Product ID
+	ProtocolType string `url:"protocolType,omitempty"` // ProtocolTypeStaking or ProtocolTypeDefi
+	Ccy          string `url:"ccy,omitempty"`          // Investment currency, e.g. BTC
+	After        string `url:"after,omitempty"`        // Pagination of data to return records earlier than the requested ordId
+	Before       string `url:"before,omitempty"`       // Pagination of data to return records newer than the requested ordId
+	Limit        string `url:"limit,omitempty"`        // Number of results per request, maximum 100
+}
+
+type GetOrdersResponse struct {
+	rest.Response
+	Data []Order `json:"data"`
+}
+
+type Order struct {
+	Ccy          string       `json:"ccy"`
+	OrdId        string       `json:"ordId"`
+	ProductId    string       `json:"productId"`
+	State        string       `json:"state"`
+	Protocol     string       `json:"protocol"`
+	ProtocolType string       `json:"protocolType"`
+	Term         string       `json:"term"`
+	Apy          string       `json:"apy"`
+	InvestData   []InvestData `json:"investData"`
+	EarningData  []struct {
+		Ccy         string `json:"ccy"`
+		EarningType string `json:"earningType"`
+		Earnings    string `json:"earnings"`
+	} `json:"earningData"`
+	PurchasedTime            string `json:"purchasedTime"`
+	RedeemedTime             string `json:"redeemedTime"`
+	EstSettlementTime        string `json:"estSettlementTime"`
+	CancelRedemptionDeadline string `json:"cancelRedemptionDeadline"`
+	Tag                      string `json:"tag"`
+}
@@ -0,0 +1,63 @@
+package staking
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+func NewPurchase(param *PurchaseParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/finance/staking-defi/purchase",
+		Method: rest.MethodPost,
+		Param:  param,
+	}, &OrderResponse{}
+}
+
+func NewRedeem(param *RedeemParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/finance/staking-defi/redeem",
+		Method: rest.MethodPost,
+		Param:  param,
+	}, &OrderResponse{}
+}
+
+func NewCancel(param *CancelParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/finance/staking-defi/cancel",
+		Method: rest.MethodPost,
+		Param:  param,
+	}, &OrderResponse{}
+}
+
+type PurchaseParam struct {
+	ProductId  string       `json:"productId"`      // Product ID
+	InvestData []InvestData `json:"investData"`     // Investment data
+	Term       string       `json:"term,omitempty"` // Investment term, required for fixed term products
+	Tag        string       `json:"tag,omitempty"`  // Order tag
+}
+
+type InvestData struct {
+	Ccy string `json:"ccy"` // Investment currency, e.g. BTC
+	Amt string `json:"amt"` // Investment amount
+}
+
+type RedeemParam struct {
+	OrdId            string `json:"ordId"`                      // Order ID
+	ProtocolType     string `json:"protocolType"`               // ProtocolTypeStaking or ProtocolTypeDefi
+	AllowEarlyRedeem bool   `json:"allowEarlyRedeem,omitempty"` // Whether to allow early redemption
+}
+
+type CancelParam struct {
+	OrdId        string `json:"ordId"`        // Order ID
+	ProtocolType string `json:"protocolType"` // ProtocolTypeStaking or ProtocolTypeDefi
+}
+
+type OrderResponse struct {
+	rest.Response
+	Data []OrderResult `json:"data"`
+}
+
+type OrderResult struct {
+	OrdId string `json:"ordId"`
+	Tag   string `json:"tag"`
+}
+
+func (p *PurchaseParam) GetTag() string    { return p.Tag }
+func (p *PurchaseParam) SetTag(tag string) { p.Tag = tag }
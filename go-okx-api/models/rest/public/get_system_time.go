@@ -0,0 +1,21 @@
+package public
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+// NewGetSystemTime returns the API server time. It needs no credentials,
+// which makes it a cheap probe of REST health.
+func NewGetSystemTime() (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/public/time",
+		Method: rest.MethodGet,
+	}, &GetSystemTimeResponse{}
+}
+
+type GetSystemTimeResponse struct {
+	rest.Response
+	Data []SystemTime `json:"data"`
+}
+
+type SystemTime struct {
+	Ts string `json:"ts"` // Server time in Unix milliseconds
+}
@@ -33,3 +33,18 @@ func (r Request) GetParam() interface{} {
 func (r Request) IsPost() bool {
 	return r.Method == MethodPost
 }
+
+// ITaggedParam is implemented by params that carry the broker tag so the
+// client can fill it in.
+type ITaggedParam interface {
+	GetTag() string
+	SetTag(tag string)
+}
+
+// IOrderParam is implemented by the params of endpoints that place orders so
+// the client can fill in a generated client order id as well as the tag.
+type IOrderParam interface {
+	ITaggedParam
+	GetClOrdId() string
+	SetClOrdId(clOrdId string)
+}
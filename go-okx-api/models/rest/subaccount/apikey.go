@@ -0,0 +1,92 @@
+package subaccount
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+const (
+	ApiKeyPermRead     = "read_only"
+	ApiKeyPermTrade    = "trade"
+	ApiKeyPermWithdraw = "withdraw"
+)
+
+func NewCreateSubaccountApiKey(param *CreateSubaccountApiKeyParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/users/subaccount/apikey",
+		Method: rest.MethodPost,
+		Param:  param,
+	}, &SubaccountApiKeyResponse{}
+}
+
+func NewGetSubaccountApiKey(param *GetSubaccountApiKeyParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/users/subaccount/apikey",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &SubaccountApiKeyResponse{}
+}
+
+func NewModifySubaccountApiKey(param *ModifySubaccountApiKeyParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/users/subaccount/modify-apikey",
+		Method: rest.MethodPost,
+		Param:  param,
+	}, &SubaccountApiKeyResponse{}
+}
+
+func NewDeleteSubaccountApiKey(param *DeleteSubaccountApiKeyParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/users/subaccount/delete-apikey",
+		Method: rest.MethodPost,
+		Param:  param,
+	}, &DeleteSubaccountApiKeyResponse{}
+}
+
+type CreateSubaccountApiKeyParam struct {
+	SubAcct    string `json:"subAcct"`        // Sub-account name
+	Label      string `json:"label"`          // API key note
+	Passphrase string `json:"passphrase"`     // API key password
+	Perm       string `json:"perm,omitempty"` // Comma separated permissions, e.g. read_only,trade
+	Ip         string `json:"ip,omitempty"`   // Comma separated IP whitelist, up to 20 addresses
+}
+
+type GetSubaccountApiKeyParam struct {
+	SubAcct string `url:"subAcct"`          // Sub-account name
+	ApiKey  string `url:"apiKey,omitempty"` // API public key
+}
+
+type ModifySubaccountApiKeyParam struct {
+	SubAcct string `json:"subAcct"`         // Sub-account name
+	ApiKey  string `json:"apiKey"`          // API public key
+	Label   string `json:"label,omitempty"` // API key note
+	Perm    string `json:"perm,omitempty"`  // Comma separated permissions, e.g. read_only,trade
+	Ip      string `json:"ip,omitempty"`    // Comma separated IP whitelist, an empty value removes all IPs
+}
+
+type DeleteSubaccountApiKeyParam struct {
+	SubAcct string `json:"subAcct"` // Sub-account name
+	ApiKey  string `json:"apiKey"`  // API public key
+}
+
+type SubaccountApiKeyResponse struct {
+	rest.Response
+	Data []SubaccountApiKey `json:"data"`
+}
+
+type SubaccountApiKey struct {
+	SubAcct    string `json:"subAcct"`
+	Label      string `json:"label"`
+	ApiKey     string `json:"apiKey"`
+	SecretKey  string `json:"secretKey,omitempty"`
+	Passphrase string `json:"passphrase,omitempty"`
+	Perm       string `json:"perm"`
+	Ip         string `json:"ip"`
+	Ts         string `json:"ts"`
+}
+
+type DeleteSubaccountApiKeyResponse struct {
+	rest.Response
+	Data []DeleteSubaccountApiKeyResult `json:"data"`
+}
+
+type DeleteSubaccountApiKeyResult struct {
+	SubAcct string `json:"subAcct"`
+}
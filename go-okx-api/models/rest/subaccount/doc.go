@@ -0,0 +1,4 @@
+// Package subaccount contains the request models for OKX sub-account
+// management. Every endpoint here acts on behalf of the master account, so the
+// RestClient must be built with the master account's common.Auth.
+package subaccount
@@ -0,0 +1,34 @@
+package subaccount
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+func NewGetSubaccountBills(param *GetSubaccountBillsParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/asset/subaccount/bills",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &GetSubaccountBillsResponse{}
+}
+
+type GetSubaccountBillsParam struct {
+	Ccy     string `url:"ccy,omitempty"`     // Currency, e.g. BTC
+	Type    string `url:"type,omitempty"`    // 0: master to sub, 1: sub to master
+	SubAcct string `url:"subAcct,omitempty"` // Sub-account name
+	After   string `url:"after,omitempty"`   // Query data earlier than the requested ts
+	Before  string `url:"before,omitempty"`  // Query data newer than the requested ts
+	Limit   string `url:"limit,omitempty"`   // Number of results per request, maximum 100
+}
+
+type GetSubaccountBillsResponse struct {
+	rest.Response
+	Data []SubaccountBill `json:"data"`
+}
+
+type SubaccountBill struct {
+	BillId  string `json:"billId"`
+	Ccy     string `json:"ccy"`
+	Amt     string `json:"amt"`
+	Type    string `json:"type"`
+	SubAcct string `json:"subAcct"`
+	Ts      string `json:"ts"`
+}
@@ -0,0 +1,28 @@
+package subaccount
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+func NewGetSubaccountFundingBalances(param *GetSubaccountFundingBalancesParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/asset/subaccount/balances",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &GetSubaccountFundingBalancesResponse{}
+}
+
+type GetSubaccountFundingBalancesParam struct {
+	SubAcct string `url:"subAcct"`       // Sub-account name
+	Ccy     string `url:"ccy,omitempty"` // Single or comma separated currencies, e.g. BTC,ETH
+}
+
+type GetSubaccountFundingBalancesResponse struct {
+	rest.Response
+	Data []FundingBalance `json:"data"`
+}
+
+type FundingBalance struct {
+	Ccy       string `json:"ccy"`
+	Bal       string `json:"bal"`
+	FrozenBal string `json:"frozenBal"`
+	AvailBal  string `json:"availBal"`
+}
@@ -0,0 +1,40 @@
+package subaccount
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+func NewGetSubaccountList(param *GetSubaccountListParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/users/subaccount/list",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &GetSubaccountListResponse{}
+}
+
+type GetSubaccountListParam struct {
+	Enable  string `url:"enable,omitempty"`  // Sub-account status, true: normal, false: frozen
+	SubAcct string `url:"subAcct,omitempty"` // Sub-account name
+	After   string `url:"after,omitempty"`   // Query data earlier than the requested subaccount creation timestamp
+	Before  string `url:"before,omitempty"`  // Query data newer than the requested subaccount creation timestamp
+	Limit   string `url:"limit,omitempty"`   // Number of results per request, maximum 100
+}
+
+type GetSubaccountListResponse struct {
+	rest.Response
+	Data []Subaccount `json:"data"`
+}
+
+type Subaccount struct {
+	Type           string   `json:"type"`
+	Enable         bool     `json:"enable"`
+	SubAcct        string   `json:"subAcct"`
+	Uid            string   `json:"uid"`
+	Label          string   `json:"label"`
+	Mobile         string   `json:"mobile"`
+	GAuth          bool     `json:"gAuth"`
+	FrozenFunc     []string `json:"frozenFunc"`
+	CanTransOut    bool     `json:"canTransOut"`
+	Ts             string   `json:"ts"`
+	SubAcctLv      string   `json:"subAcctLv"`
+	FirstLvSubAcct string   `json:"firstLvSubAcct"`
+	IfDma          bool     `json:"ifDma"`
+}
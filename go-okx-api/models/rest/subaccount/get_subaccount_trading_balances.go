@@ -0,0 +1,64 @@
+package subaccount
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+func NewGetSubaccountTradingBalances(param *GetSubaccountTradingBalancesParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/account/subaccount/balances",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &GetSubaccountTradingBalancesResponse{}
+}
+
+type GetSubaccountTradingBalancesParam struct {
+	SubAcct string `url:"subAcct"` // Sub-account name
+}
+
+type GetSubaccountTradingBalancesResponse struct {
+	rest.Response
+	Data []TradingBalance `json:"data"`
+}
+
+type TradingBalance struct {
+	UTime       string                 `json:"uTime"`
+	TotalEq     string                 `json:"totalEq"`
+	IsoEq       string                 `json:"isoEq"`
+	AdjEq       string                 `json:"adjEq"`
+	OrdFroz     string                 `json:"ordFroz"`
+	Imr         string                 `json:"imr"`
+	Mmr         string                 `json:"mmr"`
+	BorrowFroz  string                 `json:"borrowFroz"`
+	MgnRatio    string                 `json:"mgnRatio"`
+	NotionalUsd string                 `json:"notionalUsd"`
+	Upl         string                 `json:"upl"`
+	Details     []TradingBalanceDetail `json:"details"`
+}
+
+type TradingBalanceDetail struct {
+	Ccy           string `json:"ccy"`
+	Eq            string `json:"eq"`
+	CashBal       string `json:"cashBal"`
+	UTime         string `json:"uTime"`
+	IsoEq         string `json:"isoEq"`
+	AvailEq       string `json:"availEq"`
+	DisEq         string `json:"disEq"`
+	FixedBal      string `json:"fixedBal"`
+	AvailBal      string `json:"availBal"`
+	FrozenBal     string `json:"frozenBal"`
+	OrdFrozen     string `json:"ordFrozen"`
+	Liab          string `json:"liab"`
+	Upl           string `json:"upl"`
+	UplLiab       string `json:"uplLiab"`
+	CrossLiab     string `json:"crossLiab"`
+	IsoLiab       string `json:"isoLiab"`
+	MgnRatio      string `json:"mgnRatio"`
+	Interest      string `json:"interest"`
+	Twap          string `json:"twap"`
+	MaxLoan       string `json:"maxLoan"`
+	EqUsd         string `json:"eqUsd"`
+	BorrowFroz    string `json:"borrowFroz"`
+	NotionalLever string `json:"notionalLever"`
+	StgyEq        string `json:"stgyEq"`
+	IsoUpl        string `json:"isoUpl"`
+	SpotInUseAmt  string `json:"spotInUseAmt"`
+}
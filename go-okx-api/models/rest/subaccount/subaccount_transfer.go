@@ -0,0 +1,33 @@
+package subaccount
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+// NewSubaccountTransfer moves funds from one sub-account to another
+// sub-account of the same master account.
+func NewSubaccountTransfer(param *SubaccountTransferParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/asset/subaccount/transfer",
+		Method: rest.MethodPost,
+		Param:  param,
+	}, &SubaccountTransferResponse{}
+}
+
+type SubaccountTransferParam struct {
+	Ccy            string `json:"ccy"`                   // Transfer currency, e.g. USDT
+	Amt            string `json:"amt"`                   // Amount to be transferred
+	From           string `json:"from"`                  // Account type of the transferor sub-account
+	To             string `json:"to"`                    // Account type of the transferee sub-account
+	FromSubAccount string `json:"fromSubAccount"`        // Sub-account name of the transferor
+	ToSubAccount   string `json:"toSubAccount"`          // Sub-account name of the transferee
+	LoanTrans      bool   `json:"loanTrans,omitempty"`   // Whether or not borrowed coins can be transferred out
+	OmitPosRisk    string `json:"omitPosRisk,omitempty"` // Ignore position risk, applicable to Portfolio margin
+}
+
+type SubaccountTransferResponse struct {
+	rest.Response
+	Data []SubaccountTransferResult `json:"data"`
+}
+
+type SubaccountTransferResult struct {
+	TransId string `json:"transId"`
+}
@@ -0,0 +1,49 @@
+package subaccount
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+const (
+	TransferTypeMasterToSub = "1"
+	TransferTypeSubToMaster = "2"
+)
+
+const (
+	AccountTypeFunding = "6"
+	AccountTypeTrading = "18"
+)
+
+// NewTransfer moves funds between the master account and one of its
+// sub-accounts. The request must be signed with the master account's key.
+func NewTransfer(param *TransferParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/asset/transfer",
+		Method: rest.MethodPost,
+		Param:  param,
+	}, &TransferResponse{}
+}
+
+type TransferParam struct {
+	Type        string `json:"type"`                  // TransferTypeMasterToSub or TransferTypeSubToMaster
+	Ccy         string `json:"ccy"`                   // Transfer currency, e.g. USDT
+	Amt         string `json:"amt"`                   // Amount to be transferred
+	From        string `json:"from"`                  // Remitting account type, AccountTypeFunding or AccountTypeTrading
+	To          string `json:"to"`                    // Beneficiary account type, AccountTypeFunding or AccountTypeTrading
+	SubAcct     string `json:"subAcct"`               // Sub-account name
+	LoanTrans   bool   `json:"loanTrans,omitempty"`   // Whether or not borrowed coins can be transferred out
+	OmitPosRisk string `json:"omitPosRisk,omitempty"` // Ignore position risk, applicable to Portfolio margin
+	ClientId    string `json:"clientId,omitempty"`    // Client-supplied ID, 1-32 alphanumeric characters
+}
+
+type TransferResponse struct {
+	rest.Response
+	Data []TransferResult `json:"data"`
+}
+
+type TransferResult struct {
+	TransId  string `json:"transId"`
+	Ccy      string `json:"ccy"`
+	ClientId string `json:"clientId"`
+	From     string `json:"from"`
+	Amt      string `json:"amt"`
+	To       string `json:"to"`
+}
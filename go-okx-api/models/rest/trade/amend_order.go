@@ -0,0 +1,46 @@
+package trade
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+func NewAmendOrder(param *AmendOrderParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/trade/amend-order",
+		Method: rest.MethodPost,
+		Param:  param,
+	}, &AmendOrderResponse{}
+}
+
+// NewBatchAmendOrders amends up to 20 orders in a single request.
+func NewBatchAmendOrders(params []AmendOrderParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/trade/amend-batch-orders",
+		Method: rest.MethodPost,
+		Param:  params,
+	}, &AmendOrderResponse{}
+}
+
+type AmendOrderParam struct {
+	InstId    string `json:"instId"`              // Instrument ID, e.g. BTC-USDT
+	CxlOnFail bool   `json:"cxlOnFail,omitempty"` // Whether the order is cancelled when the amendment fails
+	OrdId     string `json:"ordId,omitempty"`     // Order ID, either ordId or clOrdId is required
+	ClOrdId   string `json:"clOrdId,omitempty"`   // Client Order ID as assigned by the client
+	ReqId     string `json:"reqId,omitempty"`     // Client Request ID as assigned by the client for order amendment
+	NewSz     string `json:"newSz,omitempty"`     // New quantity after amendment, either newSz or newPx is required
+	NewPx     string `json:"newPx,omitempty"`     // New price after amendment
+	NewPxUsd  string `json:"newPxUsd,omitempty"`  // Modify options orders using USD prices
+	NewPxVol  string `json:"newPxVol,omitempty"`  // Modify options orders based on implied volatility
+}
+
+type AmendOrderResponse struct {
+	rest.Response
+	Data []AmendOrderResult `json:"data"`
+}
+
+type AmendOrderResult struct {
+	OrdId   string `json:"ordId"`
+	ClOrdId string `json:"clOrdId"`
+	ReqId   string `json:"reqId"`
+	Ts      string `json:"ts"`
+	SCode   string `json:"sCode"`
+	SMsg    string `json:"sMsg"`
+}
@@ -0,0 +1,29 @@
+package trade
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+// NewCancelAllAfter arms the exchange side countdown that cancels all pending
+// orders once it expires. A TimeOut of "0" disarms it.
+func NewCancelAllAfter(param *CancelAllAfterParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/trade/cancel-all-after",
+		Method: rest.MethodPost,
+		Param:  param,
+	}, &CancelAllAfterResponse{}
+}
+
+type CancelAllAfterParam struct {
+	TimeOut string `json:"timeOut"`       // Countdown in seconds, 0 or between 10 and 120
+	Tag     string `json:"tag,omitempty"` // Only cancel orders with this tag
+}
+
+type CancelAllAfterResponse struct {
+	rest.Response
+	Data []CancelAllAfter `json:"data"`
+}
+
+type CancelAllAfter struct {
+	TriggerTime string `json:"triggerTime"`
+	Tag         string `json:"tag"`
+	Ts          string `json:"ts"`
+}
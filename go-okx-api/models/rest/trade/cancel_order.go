@@ -0,0 +1,39 @@
+package trade
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+func NewCancelOrder(param *CancelOrderParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/trade/cancel-order",
+		Method: rest.MethodPost,
+		Param:  param,
+	}, &CancelOrderResponse{}
+}
+
+// NewBatchCancelOrders cancels up to 20 orders in a single request.
+func NewBatchCancelOrders(params []CancelOrderParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/trade/cancel-batch-orders",
+		Method: rest.MethodPost,
+		Param:  params,
+	}, &CancelOrderResponse{}
+}
+
+type CancelOrderParam struct {
+	InstId  string `json:"instId"`            // Instrument ID, e.g. BTC-USDT
+	OrdId   string `json:"ordId,omitempty"`   // Order ID, either ordId or clOrdId is required
+	ClOrdId string `json:"clOrdId,omitempty"` // Client Order ID as assigned by the client
+}
+
+type CancelOrderResponse struct {
+	rest.Response
+	Data []CancelOrderResult `json:"data"`
+}
+
+type CancelOrderResult struct {
+	OrdId   string `json:"ordId"`
+	ClOrdId string `json:"clOrdId"`
+	Ts      string `json:"ts"`
+	SCode   string `json:"sCode"`
+	SMsg    string `json:"sMsg"`
+}
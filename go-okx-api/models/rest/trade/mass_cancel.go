@@ -0,0 +1,27 @@
+package trade
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+// NewMassCancel cancels all MMP pending orders of an instrument family.
+func NewMassCancel(param *MassCancelParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/trade/mass-cancel",
+		Method: rest.MethodPost,
+		Param:  param,
+	}, &MassCancelResponse{}
+}
+
+type MassCancelParam struct {
+	InstType     string `json:"instType"`               // Instrument type, OPTION
+	InstFamily   string `json:"instFamily"`             // Instrument family, e.g. BTC-USD
+	LockInterval string `json:"lockInterval,omitempty"` // Lock interval in milliseconds, between 0 and 10000
+}
+
+type MassCancelResponse struct {
+	rest.Response
+	Data []MassCancel `json:"data"`
+}
+
+type MassCancel struct {
+	Result bool `json:"result"`
+}
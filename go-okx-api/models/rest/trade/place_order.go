@@ -0,0 +1,91 @@
+package trade
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+const (
+	OrdTypeMarket          = "market"
+	OrdTypeLimit           = "limit"
+	OrdTypePostOnly        = "post_only"
+	OrdTypeFok             = "fok"
+	OrdTypeIoc             = "ioc"
+	OrdTypeOptimalLimitIoc = "optimal_limit_ioc"
+	OrdTypeMmp             = "mmp"
+	OrdTypeMmpAndPostOnly  = "mmp_and_post_only"
+)
+
+const (
+	TdModeCash     = "cash"
+	TdModeCross    = "cross"
+	TdModeIsolated = "isolated"
+)
+
+const (
+	SideBuy  = "buy"
+	SideSell = "sell"
+)
+
+const (
+	TgtCcyBase  = "base_ccy"
+	TgtCcyQuote = "quote_ccy"
+)
+
+const (
+	PosSideNet   = "net"
+	PosSideLong  = "long"
+	PosSideShort = "short"
+)
+
+func NewPlaceOrder(param *PlaceOrderParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/trade/order",
+		Method: rest.MethodPost,
+		Param:  param,
+	}, &PlaceOrderResponse{}
+}
+
+// NewBatchOrders places up to 20 orders in a single request.
+func NewBatchOrders(params []PlaceOrderParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/trade/batch-orders",
+		Method: rest.MethodPost,
+		Param:  params,
+	}, &PlaceOrderResponse{}
+}
+
+type PlaceOrderParam struct {
+	InstId     string `json:"instId"`               // Instrument ID, e.g. BTC-USDT
+	TdMode     string `json:"tdMode"`               // TdModeCash, TdModeCross or TdModeIsolated
+	Ccy        string `json:"ccy,omitempty"`        // Margin currency, only applicable to cross MARGIN orders
+	ClOrdId    string `json:"clOrdId,omitempty"`    // Client Order ID, up to 32 alphanumeric characters
+	Tag        string `json:"tag,omitempty"`        // Order tag, up to 16 alphanumeric characters
+	Side       string `json:"side"`                 // SideBuy or SideSell
+	PosSide    string `json:"posSide,omitempty"`    // PosSideLong or PosSideShort in long/short mode
+	OrdType    string `json:"ordType"`              // Order type, OrdTypeMmp and OrdTypeMmpAndPostOnly are for options MMP
+	Sz         string `json:"sz"`                   // Quantity to buy or sell
+	Px         string `json:"px,omitempty"`         // Order price, only applicable to limit, post_only, fok, ioc and mmp orders
+	PxUsd      string `json:"pxUsd,omitempty"`      // Place options orders in USD
+	PxVol      string `json:"pxVol,omitempty"`      // Place options orders based on implied volatility
+	ReduceOnly bool   `json:"reduceOnly,omitempty"` // Whether orders can only reduce in position size
+	TgtCcy     string `json:"tgtCcy,omitempty"`     // Whether the target currency uses the quote or base currency for SPOT market orders
+	BanAmend   bool   `json:"banAmend,omitempty"`   // Whether to disallow the system from amending the size of the SPOT market order
+	StpMode    string `json:"stpMode,omitempty"`    // Self trade prevention mode, cancel_maker, cancel_taker or cancel_both
+}
+
+type PlaceOrderResponse struct {
+	rest.Response
+	Data []PlaceOrderResult `json:"data"`
+}
+
+type PlaceOrderResult struct {
+	OrdId   string `json:"ordId"`
+	ClOrdId string `json:"clOrdId"`
+	Tag     string `json:"tag"`
+	Ts      string `json:"ts"`
+	SCode   string `json:"sCode"`
+	SMsg    string `json:"sMsg"`
+}
+
+func (p *PlaceOrderParam) GetClOrdId() string        { return p.ClOrdId }
+func (p *PlaceOrderParam) SetClOrdId(clOrdId string) { p.ClOrdId = clOrdId }
+func (p *PlaceOrderParam) GetTag() string            { return p.Tag }
+func (p *PlaceOrderParam) SetTag(tag string)         { p.Tag = tag }
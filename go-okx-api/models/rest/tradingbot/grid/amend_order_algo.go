@@ -0,0 +1,33 @@
+package grid
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+func NewAmendOrderAlgo(param *AmendOrderAlgoParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/tradingBot/grid/amend-order-algo",
+		Method: rest.MethodPost,
+		Param:  param,
+	}, &AmendOrderAlgoResponse{}
+}
+
+type AmendOrderAlgoParam struct {
+	AlgoId        string         `json:"algoId"`                  // Algo ID
+	InstId        string         `json:"instId"`                  // Instrument ID, e.g. BTC-USDT-SWAP
+	SlTriggerPx   string         `json:"slTriggerPx,omitempty"`   // New stop-loss trigger price
+	TpTriggerPx   string         `json:"tpTriggerPx,omitempty"`   // New take-profit trigger price
+	TpRatio       string         `json:"tpRatio,omitempty"`       // Take profit ratio, 0.1 represents 10%
+	SlRatio       string         `json:"slRatio,omitempty"`       // Stop loss ratio, 0.1 represents 10%
+	TriggerParams []TriggerParam `json:"triggerParams,omitempty"` // Trigger strategies
+}
+
+type AmendOrderAlgoResponse struct {
+	rest.Response
+	Data []AmendOrderAlgoResult `json:"data"`
+}
+
+type AmendOrderAlgoResult struct {
+	AlgoId      string `json:"algoId"`
+	AlgoClOrdId string `json:"algoClOrdId"`
+	SCode       string `json:"sCode"`
+	SMsg        string `json:"sMsg"`
+}
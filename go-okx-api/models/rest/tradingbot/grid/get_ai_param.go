@@ -0,0 +1,43 @@
+package grid
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+// NewGetAiParam returns the grid parameters OKX recommends for an instrument.
+// This is a public endpoint and does not require authentication.
+func NewGetAiParam(param *GetAiParamParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/tradingBot/grid/ai-param",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &GetAiParamResponse{}
+}
+
+type GetAiParamParam struct {
+	AlgoOrdType string `url:"algoOrdType"`         // AlgoOrdTypeGrid or AlgoOrdTypeContractGrid
+	InstId      string `url:"instId"`              // Instrument ID, e.g. BTC-USDT
+	Direction   string `url:"direction,omitempty"` // long, short or neutral, required for contract grid
+	Duration    string `url:"duration,omitempty"`  // Back testing duration, 7D, 30D or 180D
+}
+
+type GetAiParamResponse struct {
+	rest.Response
+	Data []AiParam `json:"data"`
+}
+
+type AiParam struct {
+	AlgoOrdType      string `json:"algoOrdType"`
+	AnnualizedRate   string `json:"annualizedRate"`
+	Ccy              string `json:"ccy"`
+	Direction        string `json:"direction"`
+	Duration         string `json:"duration"`
+	GridNum          string `json:"gridNum"`
+	InstId           string `json:"instId"`
+	Lever            string `json:"lever"`
+	MaxPx            string `json:"maxPx"`
+	MinInvestment    string `json:"minInvestment"`
+	MinPx            string `json:"minPx"`
+	PerMaxProfitRate string `json:"perMaxProfitRate"`
+	PerMinProfitRate string `json:"perMinProfitRate"`
+	RunType          string `json:"runType"`
+	SourceCcy        string `json:"sourceCcy"`
+}
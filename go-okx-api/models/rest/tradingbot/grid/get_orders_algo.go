@@ -0,0 +1,81 @@
+package grid
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+func NewGetOrdersAlgoPending(param *GetOrdersAlgoParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/tradingBot/grid/orders-algo-pending",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &GetOrdersAlgoResponse{}
+}
+
+func NewGetOrdersAlgoHistory(param *GetOrdersAlgoParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/tradingBot/grid/orders-algo-history",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &GetOrdersAlgoResponse{}
+}
+
+type GetOrdersAlgoParam struct {
+	AlgoOrdType string `url:"algoOrdType"`        // AlgoOrdTypeGrid or AlgoOrdTypeContractGrid
+	AlgoId      string `url:"algoId,omitempty"`   // Algo ID
+	InstId      string `url:"instId,omitempty"`   // Instrument ID, e.g. BTC-USDT
+	InstType    string `url:"instType,omitempty"` // SPOT, MARGIN, FUTURES or SWAP
+	After       string `url:"after,omitempty"`    // Pagination of data to return records earlier than the requested algoId
+	Before      string `url:"before,omitempty"`   // Pagination of data to return records newer than the requested algoId
+	Limit       string `url:"limit,omitempty"`    // Number of results per request, maximum 100
+}
+
+type GetOrdersAlgoResponse struct {
+	rest.Response
+	Data []OrderAlgo `json:"data"`
+}
+
+type OrderAlgo struct {
+	AlgoId      string `json:"algoId"`
+	AlgoClOrdId string `json:"algoClOrdId"`
+	InstType    string `json:"instType"`
+	InstId      string `json:"instId"`
+	CTime       string `json:"cTime"`
+	UTime       string `json:"uTime"`
+	AlgoOrdType string `json:"algoOrdType"`
+	State       string `json:"state"`
+	RebateTrans []struct {
+		Rebate    string `json:"rebate"`
+		RebateCcy string `json:"rebateCcy"`
+	} `json:"rebateTrans"`
+	TriggerParams      []TriggerParam `json:"triggerParams"`
+	MaxPx              string         `json:"maxPx"`
+	MinPx              string         `json:"minPx"`
+	GridNum            string         `json:"gridNum"`
+	RunType            string         `json:"runType"`
+	TpTriggerPx        string         `json:"tpTriggerPx"`
+	SlTriggerPx        string         `json:"slTriggerPx"`
+	ArbitrageNum       string         `json:"arbitrageNum"`
+	TotalPnl           string         `json:"totalPnl"`
+	PnlRatio           string         `json:"pnlRatio"`
+	Investment         string         `json:"investment"`
+	GridProfit         string         `json:"gridProfit"`
+	FloatProfit        string         `json:"floatProfit"`
+	CancelType         string         `json:"cancelType"`
+	StopType           string         `json:"stopType"`
+	QuoteSz            string         `json:"quoteSz"`
+	BaseSz             string         `json:"baseSz"`
+	Direction          string         `json:"direction"`
+	BasePos            bool           `json:"basePos"`
+	Sz                 string         `json:"sz"`
+	Lever              string         `json:"lever"`
+	ActualLever        string         `json:"actualLever"`
+	LiqPx              string         `json:"liqPx"`
+	Uly                string         `json:"uly"`
+	InstFamily         string         `json:"instFamily"`
+	OrdFrozen          string         `json:"ordFrozen"`
+	AvailEq            string         `json:"availEq"`
+	Tag                string         `json:"tag"`
+	ProfitSharingRatio string         `json:"profitSharingRatio"`
+	CopyType           string         `json:"copyType"`
+	Fee                string         `json:"fee"`
+	FundingFee         string         `json:"fundingFee"`
+}
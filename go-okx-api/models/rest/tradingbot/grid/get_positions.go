@@ -0,0 +1,46 @@
+package grid
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+func NewGetPositions(param *GetPositionsParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/tradingBot/grid/positions",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &GetPositionsResponse{}
+}
+
+type GetPositionsParam struct {
+	AlgoOrdType string `url:"algoOrdType"` // AlgoOrdTypeContractGrid
+	AlgoId      string `url:"algoId"`      // Algo ID
+}
+
+type GetPositionsResponse struct {
+	rest.Response
+	Data []Position `json:"data"`
+}
+
+type Position struct {
+	AlgoId      string `json:"algoId"`
+	AlgoClOrdId string `json:"algoClOrdId"`
+	InstType    string `json:"instType"`
+	InstId      string `json:"instId"`
+	CTime       string `json:"cTime"`
+	UTime       string `json:"uTime"`
+	AvgPx       string `json:"avgPx"`
+	Ccy         string `json:"ccy"`
+	Lever       string `json:"lever"`
+	LiqPx       string `json:"liqPx"`
+	PosSide     string `json:"posSide"`
+	Pos         string `json:"pos"`
+	MgnMode     string `json:"mgnMode"`
+	MgnRatio    string `json:"mgnRatio"`
+	Imr         string `json:"imr"`
+	Mmr         string `json:"mmr"`
+	Upl         string `json:"upl"`
+	UplRatio    string `json:"uplRatio"`
+	Last        string `json:"last"`
+	NotionalUsd string `json:"notionalUsd"`
+	Adl         string `json:"adl"`
+	MarkPx      string `json:"markPx"`
+}
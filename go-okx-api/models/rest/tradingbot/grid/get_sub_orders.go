@@ -0,0 +1,59 @@
+package grid
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+const (
+	SubOrderTypeLive   = "live"
+	SubOrderTypeFilled = "filled"
+)
+
+func NewGetSubOrders(param *GetSubOrdersParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/tradingBot/grid/sub-orders",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &GetSubOrdersResponse{}
+}
+
+type GetSubOrdersParam struct {
+	AlgoOrdType string `url:"algoOrdType"`       // AlgoOrdTypeGrid or AlgoOrdTypeContractGrid
+	AlgoId      string `url:"algoId"`            // Algo ID
+	Type        string `url:"type"`              // SubOrderTypeLive or SubOrderTypeFilled
+	GroupId     string `url:"groupId,omitempty"` // Group ID
+	After       string `url:"after,omitempty"`   // Pagination of data to return records earlier than the requested ordId
+	Before      string `url:"before,omitempty"`  // Pagination of data to return records newer than the requested ordId
+	Limit       string `url:"limit,omitempty"`   // Number of results per request, maximum 100
+}
+
+type GetSubOrdersResponse struct {
+	rest.Response
+	Data []SubOrder `json:"data"`
+}
+
+type SubOrder struct {
+	AlgoId      string `json:"algoId"`
+	AlgoClOrdId string `json:"algoClOrdId"`
+	AlgoOrdType string `json:"algoOrdType"`
+	InstType    string `json:"instType"`
+	InstId      string `json:"instId"`
+	GroupId     string `json:"groupId"`
+	OrdId       string `json:"ordId"`
+	CTime       string `json:"cTime"`
+	UTime       string `json:"uTime"`
+	TdMode      string `json:"tdMode"`
+	Ccy         string `json:"ccy"`
+	OrdType     string `json:"ordType"`
+	Sz          string `json:"sz"`
+	State       string `json:"state"`
+	Side        string `json:"side"`
+	Px          string `json:"px"`
+	Fee         string `json:"fee"`
+	FeeCcy      string `json:"feeCcy"`
+	AvgPx       string `json:"avgPx"`
+	AccFillSz   string `json:"accFillSz"`
+	PosSide     string `json:"posSide"`
+	Pnl         string `json:"pnl"`
+	CtVal       string `json:"ctVal"`
+	Lever       string `json:"lever"`
+	Tag         string `json:"tag"`
+}
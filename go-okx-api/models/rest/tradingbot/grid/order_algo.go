@@ -0,0 +1,79 @@
+package grid
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+const (
+	AlgoOrdTypeGrid         = "grid"
+	AlgoOrdTypeContractGrid = "contract_grid"
+	AlgoOrdTypeMoonGrid     = "moon_grid"
+)
+
+const (
+	RunTypeArithmetic = "1"
+	RunTypeGeometric  = "2"
+)
+
+func NewPlaceOrderAlgo(param *PlaceOrderAlgoParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/tradingBot/grid/order-algo",
+		Method: rest.MethodPost,
+		Param:  param,
+	}, &OrderAlgoResponse{}
+}
+
+type PlaceOrderAlgoParam struct {
+	InstId             string         `json:"instId"`                       // Instrument ID, e.g. BTC-USDT
+	AlgoOrdType        string         `json:"algoOrdType"`                  // AlgoOrdTypeGrid or AlgoOrdTypeContractGrid
+	MaxPx              string         `json:"maxPx"`                        // Upper price of price range
+	MinPx              string         `json:"minPx"`                        // Lower price of price range
+	GridNum            string         `json:"gridNum"`                      // Grid quantity
+	RunType            string         `json:"runType,omitempty"`            // RunTypeArithmetic or RunTypeGeometric
+	TpTriggerPx        string         `json:"tpTriggerPx,omitempty"`        // Take-profit trigger price
+	SlTriggerPx        string         `json:"slTriggerPx,omitempty"`        // Stop-loss trigger price
+	AlgoClOrdId        string         `json:"algoClOrdId,omitempty"`        // Client-supplied Algo ID
+	Tag                string         `json:"tag,omitempty"`                // Order tag
+	ProfitSharingRatio string         `json:"profitSharingRatio,omitempty"` // Profit sharing ratio, only for lead traders
+	TriggerParams      []TriggerParam `json:"triggerParams,omitempty"`      // Trigger strategies
+
+	// Spot grid
+	QuoteSz string `json:"quoteSz,omitempty"` // Invest amount for quote currency
+	BaseSz  string `json:"baseSz,omitempty"`  // Invest amount for base currency
+
+	// Contract grid
+	Sz        string `json:"sz,omitempty"`        // Used margin based on USDT
+	Direction string `json:"direction,omitempty"` // long, short or neutral
+	Lever     string `json:"lever,omitempty"`     // Leverage
+	BasePos   bool   `json:"basePos,omitempty"`   // Whether or not open a position when the strategy activates
+	TpRatio   string `json:"tpRatio,omitempty"`   // Take profit ratio, 0.1 represents 10%
+	SlRatio   string `json:"slRatio,omitempty"`   // Stop loss ratio, 0.1 represents 10%
+}
+
+type TriggerParam struct {
+	TriggerAction   string `json:"triggerAction"`          // start or stop
+	TriggerStrategy string `json:"triggerStrategy"`        // instant, price or rsi
+	DelaySeconds    string `json:"delaySeconds,omitempty"` // Delay seconds after action is triggered
+	Timeframe       string `json:"timeframe,omitempty"`    // K-line type for rsi
+	Thold           string `json:"thold,omitempty"`        // Threshold for rsi
+	TriggerCond     string `json:"triggerCond,omitempty"`  // cross_up, cross_down, above, below or cross
+	TimePeriod      string `json:"timePeriod,omitempty"`   // Time period for rsi
+	TriggerPx       string `json:"triggerPx,omitempty"`    // Trigger price for price strategy
+	StopType        string `json:"stopType,omitempty"`     // 1: sell base currency, 2: keep base currency
+}
+
+type OrderAlgoResponse struct {
+	rest.Response
+	Data []OrderAlgoResult `json:"data"`
+}
+
+type OrderAlgoResult struct {
+	AlgoId      string `json:"algoId"`
+	AlgoClOrdId string `json:"algoClOrdId"`
+	SCode       string `json:"sCode"`
+	SMsg        string `json:"sMsg"`
+	Tag         string `json:"tag"`
+}
+
+func (p *PlaceOrderAlgoParam) GetClOrdId() string        { return p.AlgoClOrdId }
+func (p *PlaceOrderAlgoParam) SetClOrdId(clOrdId string) { p.AlgoClOrdId = clOrdId }
+func (p *PlaceOrderAlgoParam) GetTag() string            { return p.Tag }
+func (p *PlaceOrderAlgoParam) SetTag(tag string)         { p.Tag = tag }
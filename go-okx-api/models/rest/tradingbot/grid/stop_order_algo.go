@@ -0,0 +1,24 @@
+package grid
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+const (
+	StopTypeSellBase = "1"
+	StopTypeKeepBase = "2"
+)
+
+// NewStopOrderAlgo stops up to 10 grid strategies in a single request.
+func NewStopOrderAlgo(params []StopOrderAlgoParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/tradingBot/grid/stop-order-algo",
+		Method: rest.MethodPost,
+		Param:  params,
+	}, &OrderAlgoResponse{}
+}
+
+type StopOrderAlgoParam struct {
+	AlgoId      string `json:"algoId"`      // Algo ID
+	InstId      string `json:"instId"`      // Instrument ID, e.g. BTC-USDT
+	AlgoOrdType string `json:"algoOrdType"` // AlgoOrdTypeGrid or AlgoOrdTypeContractGrid
+	StopType    string `json:"stopType"`    // StopTypeSellBase or StopTypeKeepBase
+}
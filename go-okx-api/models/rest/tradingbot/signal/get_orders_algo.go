@@ -0,0 +1,58 @@
+package signal
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+func NewGetOrdersAlgoPending(param *GetOrdersAlgoParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/tradingBot/signal/orders-algo-pending",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &GetOrdersAlgoResponse{}
+}
+
+func NewGetOrdersAlgoHistory(param *GetOrdersAlgoParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/tradingBot/signal/orders-algo-history",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &GetOrdersAlgoResponse{}
+}
+
+type GetOrdersAlgoParam struct {
+	AlgoOrdType string `url:"algoOrdType"`      // AlgoOrdTypeContract
+	AlgoId      string `url:"algoId,omitempty"` // Algo ID
+	After       string `url:"after,omitempty"`  // Pagination of data to return records earlier than the requested algoId
+	Before      string `url:"before,omitempty"` // Pagination of data to return records newer than the requested algoId
+	Limit       string `url:"limit,omitempty"`  // Number of results per request, maximum 100
+}
+
+type GetOrdersAlgoResponse struct {
+	rest.Response
+	Data []OrderAlgo `json:"data"`
+}
+
+type OrderAlgo struct {
+	AlgoId           string   `json:"algoId"`
+	AlgoClOrdId      string   `json:"algoClOrdId"`
+	InstType         string   `json:"instType"`
+	InstIds          []string `json:"instIds"`
+	CTime            string   `json:"cTime"`
+	UTime            string   `json:"uTime"`
+	AlgoOrdType      string   `json:"algoOrdType"`
+	State            string   `json:"state"`
+	CancelType       string   `json:"cancelType"`
+	TotalPnl         string   `json:"totalPnl"`
+	TotalPnlRatio    string   `json:"totalPnlRatio"`
+	TotalEq          string   `json:"totalEq"`
+	FloatPnl         string   `json:"floatPnl"`
+	RealizedPnl      string   `json:"realizedPnl"`
+	FrozenBal        string   `json:"frozenBal"`
+	AvailBal         string   `json:"availBal"`
+	Lever            string   `json:"lever"`
+	InvestAmt        string   `json:"investAmt"`
+	SubOrdType       string   `json:"subOrdType"`
+	Ratio            string   `json:"ratio"`
+	SignalChanId     string   `json:"signalChanId"`
+	SignalChanName   string   `json:"signalChanName"`
+	SignalSourceType string   `json:"signalSourceType"`
+}
@@ -0,0 +1,63 @@
+package signal
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+const AlgoOrdTypeContract = "contract"
+
+func NewPlaceOrderAlgo(param *PlaceOrderAlgoParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/tradingBot/signal/order-algo",
+		Method: rest.MethodPost,
+		Param:  param,
+	}, &OrderAlgoResponse{}
+}
+
+// NewStopOrderAlgo stops up to 10 signal bots in a single request.
+func NewStopOrderAlgo(params []StopOrderAlgoParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/tradingBot/signal/stop-order-algo",
+		Method: rest.MethodPost,
+		Param:  params,
+	}, &OrderAlgoResponse{}
+}
+
+type PlaceOrderAlgoParam struct {
+	SignalChanId      string        `json:"signalChanId"`                // Signal channel ID
+	Lever             string        `json:"lever"`                       // Leverage
+	InvestAmt         string        `json:"investAmt"`                   // Investment amount
+	SubOrdType        string        `json:"subOrdType"`                  // 1: limit order, 2: market order, 9: tradingView signal
+	IncludeAll        bool          `json:"includeAll,omitempty"`        // Whether to include all USDT-margined contracts
+	InstIds           []string      `json:"instIds,omitempty"`           // Instrument IDs, required when IncludeAll is false
+	Ratio             string        `json:"ratio,omitempty"`             // Price offset ratio for limit orders
+	EntrySettingParam *EntrySetting `json:"entrySettingParam,omitempty"` // Entry setting
+	ExitSettingParam  *ExitSetting  `json:"exitSettingParam,omitempty"`  // Exit setting
+}
+
+type EntrySetting struct {
+	AllowMultipleEntry bool   `json:"allowMultipleEntry,omitempty"` // Whether or not allow multiple entries in the same direction
+	EntryType          string `json:"entryType,omitempty"`          // 1: TradingView signal, 2: fixed margin, 3: contracts, 4: percentage of free margin, 5: percentage of initial invested margin
+	Amt                string `json:"amt,omitempty"`                // Amount per order
+	Ratio              string `json:"ratio,omitempty"`              // Amount ratio per order
+}
+
+type ExitSetting struct {
+	TpSlType string `json:"tpSlType"`        // pnl or price
+	TpPct    string `json:"tpPct,omitempty"` // Take-profit percentage
+	SlPct    string `json:"slPct,omitempty"` // Stop-loss percentage
+}
+
+type StopOrderAlgoParam struct {
+	AlgoId string `json:"algoId"` // Algo ID
+}
+
+type OrderAlgoResponse struct {
+	rest.Response
+	Data []OrderAlgoResult `json:"data"`
+}
+
+type OrderAlgoResult struct {
+	AlgoId      string `json:"algoId"`
+	AlgoClOrdId string `json:"algoClOrdId"`
+	SCode       string `json:"sCode"`
+	SMsg        string `json:"sMsg"`
+}
@@ -0,0 +1,55 @@
+package signal
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+func NewCreateSignal(param *CreateSignalParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/tradingBot/signal/create-signal",
+		Method: rest.MethodPost,
+		Param:  param,
+	}, &CreateSignalResponse{}
+}
+
+func NewGetSignals(param *GetSignalsParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/tradingBot/signal/signals",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &GetSignalsResponse{}
+}
+
+type CreateSignalParam struct {
+	SignalChanName string `json:"signalChanName"`           // Signal channel name
+	SignalChanDesc string `json:"signalChanDesc,omitempty"` // Signal channel description
+}
+
+type CreateSignalResponse struct {
+	rest.Response
+	Data []CreateSignalResult `json:"data"`
+}
+
+type CreateSignalResult struct {
+	SignalChanId    string `json:"signalChanId"`
+	SignalChanToken string `json:"signalChanToken"`
+}
+
+type GetSignalsParam struct {
+	SignalSourceType string `url:"signalSourceType"`       // 1: created by yourself, 2: subscribed, 3: free signal
+	SignalChanId     string `url:"signalChanId,omitempty"` // Signal channel ID
+	After            string `url:"after,omitempty"`        // Pagination of data to return records earlier than the requested signalChanId
+	Before           string `url:"before,omitempty"`       // Pagination of data to return records newer than the requested signalChanId
+	Limit            string `url:"limit,omitempty"`        // Number of results per request, maximum 100
+}
+
+type GetSignalsResponse struct {
+	rest.Response
+	Data []Signal `json:"data"`
+}
+
+type Signal struct {
+	SignalChanId     string `json:"signalChanId"`
+	SignalChanName   string `json:"signalChanName"`
+	SignalChanDesc   string `json:"signalChanDesc"`
+	SignalChanToken  string `json:"signalChanToken"`
+	SignalSourceType string `json:"signalSourceType"`
+}
@@ -0,0 +1,99 @@
+package signal
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/rest"
+
+func NewGetSubOrders(param *GetSubOrdersParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/tradingBot/signal/sub-orders",
+		Method: rest.MethodGet,
+		Param:  param,
+	}, &GetSubOrdersResponse{}
+}
+
+func NewPlaceSubOrder(param *PlaceSubOrderParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/tradingBot/signal/sub-order",
+		Method: rest.MethodPost,
+		Param:  param,
+	}, &rest.Response{}
+}
+
+func NewCancelSubOrder(param *CancelSubOrderParam) (rest.IRequest, rest.IResponse) {
+	return &rest.Request{
+		Path:   "/api/v5/tradingBot/signal/cancel-sub-order",
+		Method: rest.MethodPost,
+		Param:  param,
+	}, &CancelSubOrderResponse{}
+}
+
+type GetSubOrdersParam struct {
+	AlgoOrdType string `url:"algoOrdType"`           // AlgoOrdTypeContract
+	AlgoId      string `url:"algoId"`                // Algo ID
+	State       string `url:"state,omitempty"`       // live, partially_filled, filled or cancelled
+	SignalOrdId string `url:"signalOrdId,omitempty"` // Sub order ID
+	After       string `url:"after,omitempty"`       // Pagination of data to return records earlier than the requested ordId
+	Before      string `url:"before,omitempty"`      // Pagination of data to return records newer than the requested ordId
+	Limit       string `url:"limit,omitempty"`       // Number of results per request, maximum 100
+	Type        string `url:"type,omitempty"`        // live or filled
+	ClOrdId     string `url:"clOrdId,omitempty"`     // Client order ID
+}
+
+type GetSubOrdersResponse struct {
+	rest.Response
+	Data []SubOrder `json:"data"`
+}
+
+type SubOrder struct {
+	AlgoId      string `json:"algoId"`
+	AlgoClOrdId string `json:"algoClOrdId"`
+	AlgoOrdType string `json:"algoOrdType"`
+	InstType    string `json:"instType"`
+	InstId      string `json:"instId"`
+	OrdId       string `json:"ordId"`
+	ClOrdId     string `json:"clOrdId"`
+	CTime       string `json:"cTime"`
+	UTime       string `json:"uTime"`
+	TdMode      string `json:"tdMode"`
+	Ccy         string `json:"ccy"`
+	OrdType     string `json:"ordType"`
+	Sz          string `json:"sz"`
+	State       string `json:"state"`
+	Side        string `json:"side"`
+	Px          string `json:"px"`
+	Fee         string `json:"fee"`
+	FeeCcy      string `json:"feeCcy"`
+	AvgPx       string `json:"avgPx"`
+	AccFillSz   string `json:"accFillSz"`
+	PosSide     string `json:"posSide"`
+	Pnl         string `json:"pnl"`
+	CtVal       string `json:"ctVal"`
+	Lever       string `json:"lever"`
+	Tag         string `json:"tag"`
+}
+
+type PlaceSubOrderParam struct {
+	AlgoId     string `json:"algoId"`               // Algo ID
+	InstId     string `json:"instId"`               // Instrument ID, e.g. BTC-USDT-SWAP
+	Side       string `json:"side"`                 // buy or sell
+	OrdType    string `json:"ordType"`              // market or limit
+	Sz         string `json:"sz"`                   // Quantity to buy or sell
+	Px         string `json:"px,omitempty"`         // Order price, only applicable to limit order
+	ReduceOnly bool   `json:"reduceOnly,omitempty"` // Whether orders can only reduce in position size
+}
+
+type CancelSubOrderParam struct {
+	AlgoId      string `json:"algoId"`      // Algo ID
+	InstId      string `json:"instId"`      // Instrument ID, e.g. BTC-USDT-SWAP
+	SignalOrdId string `json:"signalOrdId"` // Sub order ID
+}
+
+type CancelSubOrderResponse struct {
+	rest.Response
+	Data []CancelSubOrderResult `json:"data"`
+}
+
+type CancelSubOrderResult struct {
+	SignalOrdId string `json:"signalOrdId"`
+	SCode       string `json:"sCode"`
+	SMsg        string `json:"sMsg"`
+}
@@ -1,6 +1,11 @@
 package ws
 
-import "cadenza-market-connector-okx/pkg/go-okx-api/common"
+import (
+	"errors"
+	"fmt"
+
+	"cadenza-market-connector-okx/pkg/go-okx-api/common"
+)
 
 type LoginArgs struct {
 	ApiKey     string `json:"apiKey"`
@@ -17,4 +22,56 @@ func NewLoginArgsFromAuth(auth common.Auth) *LoginArgs {
 		Sign:       signature.Build(),
 		Timestamp:  signature.Timestamp,
 	}
-}
\ No newline at end of file
+}
+
+var (
+	ErrLoginInvalidApiKey     = errors.New("invalid api key")
+	ErrLoginInvalidPassphrase = errors.New("invalid passphrase")
+	ErrLoginInvalidTimestamp  = errors.New("invalid or expired timestamp")
+	ErrLoginInvalidSign       = errors.New("invalid signature")
+	ErrLoginFailed            = errors.New("login failed")
+)
+
+// LoginError is a login rejected by OKX. It unwraps to one of the ErrLogin
+// errors describing the cause.
+type LoginError struct {
+	Code    string
+	Message string
+	Cause   error
+}
+
+func NewLoginError(code, message string) LoginError {
+	cause := ErrLoginFailed
+	switch code {
+	case "60004", "60006":
+		cause = ErrLoginInvalidTimestamp
+	case "60005":
+		cause = ErrLoginInvalidApiKey
+	case "60007":
+		cause = ErrLoginInvalidSign
+	case "60024":
+		cause = ErrLoginInvalidPassphrase
+	}
+	return LoginError{
+		Code:    code,
+		Message: message,
+		Cause:   cause,
+	}
+}
+
+func (e LoginError) Error() string {
+	return fmt.Sprintf("%v: code: %s, message: %s", e.Cause, e.Code, e.Message)
+}
+
+func (e LoginError) Unwrap() error {
+	return e.Cause
+}
+
+// IsLoginErrorCode reports whether an error event code is a login failure.
+func IsLoginErrorCode(code string) bool {
+	switch code {
+	case "60004", "60005", "60006", "60007", "60008", "60009", "60022", "60024", "60026":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package private
+
+import "cadenza-market-connector-okx/pkg/go-okx-api/models/ws"
+
+const (
+	CancelSourceCancelAllAfter = "20"
+	CancelSourceMmpCancel      = "38"
+	CancelSourceMmpTriggered   = "39"
+)
+
+type OrderEvent struct {
+	Arg  ws.Args `json:"arg"`
+	Data []Order `json:"data"`
+}
+
+type Order struct {
+	InstType           string `json:"instType"`
+	InstId             string `json:"instId"`
+	Ccy                string `json:"ccy"`
+	OrdId              string `json:"ordId"`
+	ClOrdId            string `json:"clOrdId"`
+	Tag                string `json:"tag"`
+	Px                 string `json:"px"`
+	Sz                 string `json:"sz"`
+	NotionalUsd        string `json:"notionalUsd"`
+	OrdType            string `json:"ordType"`
+	Side               string `json:"side"`
+	PosSide            string `json:"posSide"`
+	TdMode             string `json:"tdMode"`
+	FillPx             string `json:"fillPx"`
+	TradeId            string `json:"tradeId"`
+	FillSz             string `json:"fillSz"`
+	FillTime           string `json:"fillTime"`
+	AccFillSz          string `json:"accFillSz"`
+	AvgPx              string `json:"avgPx"`
+	State              string `json:"state"`
+	Lever              string `json:"lever"`
+	Fee                string `json:"fee"`
+	FeeCcy             string `json:"feeCcy"`
+	Pnl                string `json:"pnl"`
+	Category           string `json:"category"`
+	ReduceOnly         string `json:"reduceOnly"`
+	CancelSource       string `json:"cancelSource"`
+	CancelSourceReason string `json:"cancelSourceReason"`
+	AmendResult        string `json:"amendResult"`
+	Code               string `json:"code"`
+	Msg                string `json:"msg"`
+	UTime              int64  `json:"uTime,string"`
+	CTime              int64  `json:"cTime,string"`
+}
+
+// IsMmpTriggered reports whether the order was cancelled because market maker
+// protection was triggered for its instrument family.
+func (o Order) IsMmpTriggered() bool {
+	return o.State == "canceled" && o.CancelSource == CancelSourceMmpTriggered
+}
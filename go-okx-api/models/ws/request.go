@@ -5,8 +5,10 @@ import (
 )
 
 type Request struct {
-	Op   string      `json:"op"`
-	Args interface{} `json:"args"`
+	Id      string      `json:"id,omitempty"`
+	Op      string      `json:"op"`
+	ExpTime string      `json:"expTime,omitempty"`
+	Args    interface{} `json:"args"`
 }
 
 // new request for subscribe
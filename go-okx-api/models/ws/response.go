@@ -1 +1,36 @@
 package ws
+
+import "fmt"
+
+// Event is the reply OKX sends to an op such as login, subscribe or
+// unsubscribe, or an error event when the op was rejected.
+type Event struct {
+	Event  string `json:"event"`
+	Id     string `json:"id,omitempty"`
+	Arg    *Args  `json:"arg,omitempty"`
+	Code   string `json:"code,omitempty"`
+	Msg    string `json:"msg,omitempty"`
+	ConnId string `json:"connId,omitempty"`
+}
+
+func (e Event) IsError() bool {
+	return e.Event == "error" || (e.Code != "" && e.Code != "0")
+}
+
+type OKXError struct {
+	Code    string `json:"code"`
+	Message string `json:"msg"`
+}
+
+func NewOKXError(code, message string) OKXError {
+	return OKXError{
+		Code:    code,
+		Message: message,
+	}
+}
+
+var _ error = (*OKXError)(nil)
+
+func (e OKXError) Error() string {
+	return fmt.Sprintf("code: %s, message: %s", e.Code, e.Message)
+}
@@ -0,0 +1,48 @@
+package ws
+
+import "encoding/json"
+
+// OpResponse is the reply to a trading op sent over the private websocket,
+// correlated with its request by Id. Code is "0" when every order succeeded,
+// "1" when all failed and "2" when a batch partially succeeded.
+type OpResponse struct {
+	Id      string          `json:"id"`
+	Op      string          `json:"op"`
+	Code    string          `json:"code"`
+	Msg     string          `json:"msg"`
+	Data    json.RawMessage `json:"data"`
+	InTime  string          `json:"inTime"`
+	OutTime string          `json:"outTime"`
+}
+
+func (r OpResponse) IsOk() bool {
+	return r.Code == "0"
+}
+
+// OrderResults decodes the per-order outcome of an order, cancel or amend op.
+func (r OpResponse) OrderResults() ([]OrderResult, error) {
+	var results []OrderResult
+	if len(r.Data) == 0 {
+		return results, nil
+	}
+	err := json.Unmarshal(r.Data, &results)
+	return results, err
+}
+
+type OrderResult struct {
+	OrdId   string `json:"ordId"`
+	ClOrdId string `json:"clOrdId"`
+	Tag     string `json:"tag"`
+	ReqId   string `json:"reqId"`
+	Ts      string `json:"ts"`
+	SCode   string `json:"sCode"`
+	SMsg    string `json:"sMsg"`
+}
+
+// Err returns the rejection of this order, nil if it was accepted.
+func (r OrderResult) Err() error {
+	if r.SCode == "" || r.SCode == "0" {
+		return nil
+	}
+	return NewOKXError(r.SCode, r.SMsg)
+}
@@ -0,0 +1,227 @@
+package okx
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/rest/account"
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/rest/public"
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/rest/trade"
+)
+
+var (
+	ErrInstrumentNotLive = errors.New("instrument is not live")
+	ErrInvalidPrice      = errors.New("invalid order price")
+	ErrInvalidSize       = errors.New("invalid order size")
+	ErrSizeBelowMin      = errors.New("order size below minimum")
+	ErrReduceOnlySpot    = errors.New("reduce-only is not supported for SPOT orders")
+)
+
+type Rounding int
+
+const (
+	RoundNearest Rounding = iota
+	RoundDown
+	RoundUp
+	// RoundPassive rounds buy prices down and sell prices up, away from the
+	// touch.
+	RoundPassive
+	// RoundAggressive rounds buy prices up and sell prices down, towards the
+	// touch.
+	RoundAggressive
+)
+
+// OrderBuilder validates orders against an instrument's specification and
+// rounds them to valid increments before anything is sent to OKX.
+type OrderBuilder struct {
+	Instrument public.Instrument
+	// PosMode is the account position mode, account.PosModeNet or
+	// account.PosModeLongShort.
+	PosMode string
+	// MarginMode is the tdMode used for everything but cash SPOT orders,
+	// trade.TdModeCross by default.
+	MarginMode string
+	// SpotMargin trades SPOT with MarginMode instead of trade.TdModeCash.
+	SpotMargin    bool
+	PriceRounding Rounding
+	SizeRounding  Rounding
+}
+
+func NewOrderBuilder(instrument public.Instrument, posMode string) *OrderBuilder {
+	return &OrderBuilder{
+		Instrument:    instrument,
+		PosMode:       posMode,
+		MarginMode:    trade.TdModeCross,
+		PriceRounding: RoundNearest,
+		SizeRounding:  RoundDown,
+	}
+}
+
+// Build returns the params of an order with price and size rounded to the
+// instrument's tick and lot size, and tdMode and posSide chosen for the
+// instrument type and position mode. A reduceOnly order closes the position
+// on the opposite side. px is ignored for market orders. sz is always in the
+// base currency: SPOT market orders are sent with tgtCcy base_ccy, as OKX
+// would otherwise read the size of a market buy in the quote currency.
+func (b *OrderBuilder) Build(side, ordType, px, sz string, reduceOnly bool) (*trade.PlaceOrderParam, error) {
+	inst := b.Instrument
+	if inst.State != "live" {
+		return nil, fmt.Errorf("%w: %s is %s", ErrInstrumentNotLive, inst.InstId, inst.State)
+	}
+	if side != trade.SideBuy && side != trade.SideSell {
+		return nil, fmt.Errorf("invalid order side %q", side)
+	}
+	if reduceOnly && inst.InstType == "SPOT" {
+		return nil, ErrReduceOnlySpot
+	}
+
+	param := &trade.PlaceOrderParam{
+		InstId:  inst.InstId,
+		Side:    side,
+		OrdType: ordType,
+	}
+
+	if ordType != trade.OrdTypeMarket && ordType != trade.OrdTypeOptimalLimitIoc {
+		rounded, err := roundToIncrement(px, inst.TickSz, resolveRounding(b.PriceRounding, side))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPrice, err)
+		}
+		if rounded.Sign() <= 0 {
+			return nil, fmt.Errorf("%w: %s rounds to zero", ErrInvalidPrice, px)
+		}
+		param.Px = formatIncrement(rounded, inst.TickSz)
+	}
+	if ordType == trade.OrdTypeMarket && inst.InstType == "SPOT" {
+		param.TgtCcy = trade.TgtCcyBase
+	}
+
+	size, err := roundToIncrement(sz, inst.LotSz, resolveRounding(b.SizeRounding, side))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSize, err)
+	}
+	if minSz, ok := new(big.Rat).SetString(inst.MinSz); ok && size.Cmp(minSz) < 0 {
+		return nil, fmt.Errorf("%w: %s rounds to %s, minimum is %s", ErrSizeBelowMin, sz, formatIncrement(size, inst.LotSz), inst.MinSz)
+	}
+	if size.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: %s rounds to zero", ErrInvalidSize, sz)
+	}
+	param.Sz = formatIncrement(size, inst.LotSz)
+
+	param.TdMode = b.tdMode()
+	switch inst.InstType {
+	case "FUTURES", "SWAP":
+		if b.PosMode == account.PosModeLongShort {
+			param.PosSide = positionSide(side, reduceOnly)
+		} else {
+			param.ReduceOnly = reduceOnly
+		}
+	case "MARGIN", "OPTION":
+		param.ReduceOnly = reduceOnly
+	}
+
+	return param, nil
+}
+
+// ContractSize converts a quantity of the base currency into a number of
+// contracts rounded to the lot size. Inverse contracts are valued in the quote
+// currency, so px is needed to convert them.
+func (b *OrderBuilder) ContractSize(qty, px string) (string, error) {
+	inst := b.Instrument
+	q, ok := new(big.Rat).SetString(qty)
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrInvalidSize, qty)
+	}
+	ctVal, ok := new(big.Rat).SetString(inst.CtVal)
+	if !ok || ctVal.Sign() <= 0 {
+		return "", fmt.Errorf("instrument %s has no contract value", inst.InstId)
+	}
+
+	if inst.CtType == "inverse" {
+		p, ok := new(big.Rat).SetString(px)
+		if !ok || p.Sign() <= 0 {
+			return "", fmt.Errorf("%w: %q", ErrInvalidPrice, px)
+		}
+		q.Mul(q, p)
+	}
+	contracts := q.Quo(q, ctVal)
+
+	size, err := roundToIncrement(contracts.RatString(), inst.LotSz, RoundDown)
+	if err != nil {
+		return "", err
+	}
+	return formatIncrement(size, inst.LotSz), nil
+}
+
+func (b *OrderBuilder) tdMode() string {
+	if b.Instrument.InstType == "SPOT" && !b.SpotMargin {
+		return trade.TdModeCash
+	}
+	if b.MarginMode == "" {
+		return trade.TdModeCross
+	}
+	return b.MarginMode
+}
+
+// positionSide returns the posSide of an order in long/short mode. Buying
+// opens a long or closes a short, selling opens a short or closes a long.
+func positionSide(side string, reduceOnly bool) string {
+	if (side == trade.SideBuy) != reduceOnly {
+		return trade.PosSideLong
+	}
+	return trade.PosSideShort
+}
+
+func resolveRounding(r Rounding, side string) Rounding {
+	switch r {
+	case RoundPassive:
+		if side == trade.SideBuy {
+			return RoundDown
+		}
+		return RoundUp
+	case RoundAggressive:
+		if side == trade.SideBuy {
+			return RoundUp
+		}
+		return RoundDown
+	}
+	return r
+}
+
+// roundToIncrement rounds value to a multiple of increment using exact
+// decimal arithmetic.
+func roundToIncrement(value, increment string, r Rounding) (*big.Rat, error) {
+	v, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return nil, fmt.Errorf("cannot parse %q", value)
+	}
+	inc, ok := new(big.Rat).SetString(increment)
+	if !ok || inc.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid increment %q", increment)
+	}
+
+	steps := new(big.Rat).Quo(v, inc)
+	if r == RoundNearest {
+		steps.Add(steps, big.NewRat(1, 2))
+	}
+	n, rem := new(big.Int).QuoRem(steps.Num(), steps.Denom(), new(big.Int))
+	if rem.Sign() != 0 {
+		// QuoRem truncates towards zero, adjust to floor or ceiling
+		if r == RoundUp && rem.Sign() > 0 {
+			n.Add(n, big.NewInt(1))
+		} else if r != RoundUp && rem.Sign() < 0 {
+			n.Sub(n, big.NewInt(1))
+		}
+	}
+
+	return new(big.Rat).Mul(new(big.Rat).SetInt(n), inc), nil
+}
+
+func formatIncrement(r *big.Rat, increment string) string {
+	decimals := 0
+	if i := strings.IndexByte(increment, '.'); i >= 0 {
+		decimals = len(strings.TrimRight(increment[i+1:], "0"))
+	}
+	return r.FloatString(decimals)
+}
@@ -0,0 +1,200 @@
+package okx
+
+import (
+	"errors"
+	"testing"
+
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/rest/account"
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/rest/public"
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/rest/trade"
+)
+
+func spotInstrument() public.Instrument {
+	return public.Instrument{
+		InstId:   "BTC-USDT",
+		InstType: "SPOT",
+		TickSz:   "0.1",
+		LotSz:    "0.00001",
+		MinSz:    "0.0001",
+		State:    "live",
+	}
+}
+
+func swapInstrument() public.Instrument {
+	return public.Instrument{
+		InstId:   "BTC-USDT-SWAP",
+		InstType: "SWAP",
+		TickSz:   "0.5",
+		LotSz:    "1",
+		MinSz:    "1",
+		CtVal:    "0.01",
+		CtType:   "linear",
+		State:    "live",
+	}
+}
+
+func TestRoundToIncrement(t *testing.T) {
+	tests := []struct {
+		value     string
+		increment string
+		rounding  Rounding
+		want      string
+	}{
+		{"100.26", "0.1", RoundNearest, "100.3"},
+		{"100.25", "0.1", RoundNearest, "100.3"},
+		{"100.24", "0.1", RoundNearest, "100.2"},
+		{"100.29", "0.1", RoundDown, "100.2"},
+		{"100.21", "0.1", RoundUp, "100.3"},
+		{"100.2", "0.1", RoundUp, "100.2"},
+		{"7", "5", RoundDown, "5"},
+		{"7", "5", RoundUp, "10"},
+		{"0.000019", "0.00001", RoundDown, "0.00001"},
+		{"-1.5", "1", RoundDown, "-2"},
+		{"-1.5", "1", RoundUp, "-1"},
+	}
+	for _, tt := range tests {
+		got, err := roundToIncrement(tt.value, tt.increment, tt.rounding)
+		if err != nil {
+			t.Errorf("roundToIncrement(%s, %s, %d): %v", tt.value, tt.increment, tt.rounding, err)
+			continue
+		}
+		if s := formatIncrement(got, tt.increment); s != tt.want {
+			t.Errorf("roundToIncrement(%s, %s, %d) = %s, want %s", tt.value, tt.increment, tt.rounding, s, tt.want)
+		}
+	}
+}
+
+func TestRoundToIncrementInvalid(t *testing.T) {
+	tests := []struct{ value, increment string }{
+		{"abc", "0.1"},
+		{"1", ""},
+		{"1", "0"},
+		{"1", "-0.1"},
+	}
+	for _, tt := range tests {
+		if _, err := roundToIncrement(tt.value, tt.increment, RoundNearest); err == nil {
+			t.Errorf("roundToIncrement(%q, %q) succeeded", tt.value, tt.increment)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		name       string
+		instrument public.Instrument
+		posMode    string
+		side       string
+		ordType    string
+		px, sz     string
+		reduceOnly bool
+		want       trade.PlaceOrderParam
+		wantErr    error
+	}{
+		{
+			name:       "spot limit rounds price to nearest and size down",
+			instrument: spotInstrument(),
+			side:       trade.SideBuy, ordType: trade.OrdTypeLimit, px: "30000.06", sz: "0.123456",
+			want: trade.PlaceOrderParam{InstId: "BTC-USDT", TdMode: trade.TdModeCash, Side: trade.SideBuy, OrdType: trade.OrdTypeLimit, Px: "30000.1", Sz: "0.12345"},
+		},
+		{
+			name:       "spot market buy is sized in the base currency",
+			instrument: spotInstrument(),
+			side:       trade.SideBuy, ordType: trade.OrdTypeMarket, px: "", sz: "0.5",
+			want: trade.PlaceOrderParam{InstId: "BTC-USDT", TdMode: trade.TdModeCash, Side: trade.SideBuy, OrdType: trade.OrdTypeMarket, Sz: "0.50000", TgtCcy: trade.TgtCcyBase},
+		},
+		{
+			name:       "spot size below minimum",
+			instrument: spotInstrument(),
+			side:       trade.SideSell, ordType: trade.OrdTypeLimit, px: "30000", sz: "0.00009",
+			wantErr: ErrSizeBelowMin,
+		},
+		{
+			name:       "spot size rounded down to the minimum",
+			instrument: spotInstrument(),
+			side:       trade.SideSell, ordType: trade.OrdTypeLimit, px: "30000", sz: "0.000109",
+			want: trade.PlaceOrderParam{InstId: "BTC-USDT", TdMode: trade.TdModeCash, Side: trade.SideSell, OrdType: trade.OrdTypeLimit, Px: "30000.0", Sz: "0.00010"},
+		},
+		{
+			name:       "spot reduce-only",
+			instrument: spotInstrument(),
+			side:       trade.SideSell, ordType: trade.OrdTypeMarket, sz: "1",
+			reduceOnly: true,
+			wantErr:    ErrReduceOnlySpot,
+		},
+		{
+			name:       "price rounding to zero",
+			instrument: spotInstrument(),
+			side:       trade.SideBuy, ordType: trade.OrdTypeLimit, px: "0.04", sz: "1",
+			wantErr: ErrInvalidPrice,
+		},
+		{
+			name:       "unparseable size",
+			instrument: spotInstrument(),
+			side:       trade.SideBuy, ordType: trade.OrdTypeLimit, px: "1", sz: "one",
+			wantErr: ErrInvalidSize,
+		},
+		{
+			name:       "swap long/short closes on the opposite side",
+			instrument: swapInstrument(),
+			posMode:    account.PosModeLongShort,
+			side:       trade.SideSell, ordType: trade.OrdTypeLimit, px: "30000.3", sz: "2.7",
+			reduceOnly: true,
+			want:       trade.PlaceOrderParam{InstId: "BTC-USDT-SWAP", TdMode: trade.TdModeCross, Side: trade.SideSell, PosSide: trade.PosSideLong, OrdType: trade.OrdTypeLimit, Px: "30000.5", Sz: "2"},
+		},
+		{
+			name:       "swap net mode keeps reduce-only",
+			instrument: swapInstrument(),
+			posMode:    account.PosModeNet,
+			side:       trade.SideBuy, ordType: trade.OrdTypeMarket, sz: "3",
+			reduceOnly: true,
+			want:       trade.PlaceOrderParam{InstId: "BTC-USDT-SWAP", TdMode: trade.TdModeCross, Side: trade.SideBuy, OrdType: trade.OrdTypeMarket, Sz: "3", ReduceOnly: true},
+		},
+		{
+			name:       "swap size below minimum",
+			instrument: swapInstrument(),
+			side:       trade.SideBuy, ordType: trade.OrdTypeMarket, sz: "0.9",
+			wantErr: ErrSizeBelowMin,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewOrderBuilder(tt.instrument, tt.posMode).Build(tt.side, tt.ordType, tt.px, tt.sz, tt.reduceOnly)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got error %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *got != tt.want {
+				t.Errorf("got %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildRounding(t *testing.T) {
+	tests := []struct {
+		rounding Rounding
+		side     string
+		want     string
+	}{
+		{RoundPassive, trade.SideBuy, "100.0"},
+		{RoundPassive, trade.SideSell, "100.1"},
+		{RoundAggressive, trade.SideBuy, "100.1"},
+		{RoundAggressive, trade.SideSell, "100.0"},
+	}
+	for _, tt := range tests {
+		b := NewOrderBuilder(spotInstrument(), "")
+		b.PriceRounding = tt.rounding
+		got, err := b.Build(tt.side, trade.OrdTypeLimit, "100.04", "1", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Px != tt.want {
+			t.Errorf("rounding %d %s: px %s, want %s", tt.rounding, tt.side, got.Px, tt.want)
+		}
+	}
+}
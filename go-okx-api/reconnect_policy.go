@@ -0,0 +1,58 @@
+package okx
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// ReconnectPolicy controls how a WSClient re-dials after losing its
+// connection: exponential backoff starting at InitialDelay, capped at
+// MaxDelay and randomised by Jitter.
+type ReconnectPolicy struct {
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	Multiplier   float64
+	// Jitter is the fraction of the delay that is randomised, between 0 and 1.
+	Jitter float64
+	// MaxAttempts of 0 retries forever.
+	MaxAttempts int
+	// OnGiveUp is called once MaxAttempts consecutive attempts have failed.
+	OnGiveUp func(endpointType string, err error)
+}
+
+func DefaultReconnectPolicy() *ReconnectPolicy {
+	return &ReconnectPolicy{
+		InitialDelay: time.Second,
+		MaxDelay:     time.Minute,
+		Multiplier:   2,
+		Jitter:       0.2,
+		MaxAttempts:  MaxReconnectAttempts,
+	}
+}
+
+// Delay returns how long to wait before the given attempt, counting from 1.
+func (p *ReconnectPolicy) Delay(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	delay := float64(p.InitialDelay) * math.Pow(multiplier, float64(attempt-1))
+	if p.Jitter > 0 {
+		delay *= 1 - p.Jitter + 2*p.Jitter*rand.Float64()
+	}
+	// cap after the jitter, which could otherwise exceed MaxDelay
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+	// without MaxDelay the backoff outgrows a Duration after enough attempts
+	if delay >= math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(delay)
+}
+
+// exhausted reports whether no further attempt is allowed after attempt.
+func (p *ReconnectPolicy) exhausted(attempt int) bool {
+	return p.MaxAttempts > 0 && attempt >= p.MaxAttempts
+}
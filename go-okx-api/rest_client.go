@@ -3,6 +3,7 @@ package okx
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"time"
 	"github.com/google/go-querystring/query"
 	"github.com/valyala/fasthttp"
@@ -25,6 +26,14 @@ type RestClient struct {
 	Host string
 	Auth common.Auth
 	C    *fasthttp.Client
+	// Tag is set on every order param that does not carry one yet.
+	Tag string
+	// ClOrdIds fills in the client order id of order params that have none.
+	ClOrdIds *common.ClOrdIdGenerator
+	// Logger receives a debug record of every request, nil discards them.
+	Logger Logger
+	// Metrics records the latency of every request, nil discards it.
+	Metrics Metrics
 }
 
 // new *Client
@@ -43,8 +52,22 @@ func NewRestClient(host string, auth common.Auth, c *fasthttp.Client) *RestClien
 	}
 }
 
+// ForStrategy returns a copy of the client that stamps orders with clOrdIds
+// carrying the given strategy prefix.
+func (c *RestClient) ForStrategy(prefix string) (*RestClient, error) {
+	ids, err := common.NewClOrdIdGenerator(prefix)
+	if err != nil {
+		return nil, err
+	}
+	strategy := *c
+	strategy.ClOrdIds = ids
+	return &strategy, nil
+}
+
 // do request
 func (c *RestClient) Do(req rest.IRequest, resp rest.IResponse) error {
+	injectOrderIds(req.GetParam(), c.ClOrdIds, c.Tag)
+
 	data, err := c.do(req)
 	if err != nil {
 		return err
@@ -69,9 +92,15 @@ func (c *RestClient) do(r rest.IRequest) ([]byte, error) {
 		fasthttp.ReleaseResponse(resp)
 	}()
 
+	start := time.Now()
 	if err := c.C.Do(req, resp); err != nil {
+		c.stats().RestRequest(r.GetMethod(), r.GetPath(), 0, time.Since(start))
+		c.log().Warn("rest request failed", "method", r.GetMethod(), "path", r.GetPath(), "error", err)
 		return nil, err
 	}
+	latency := time.Since(start)
+	c.stats().RestRequest(r.GetMethod(), r.GetPath(), resp.StatusCode(), latency)
+	c.log().Debug("rest request", "method", r.GetMethod(), "path", r.GetPath(), "status", resp.StatusCode(), "latency", latency)
 	if resp.StatusCode() != fasthttp.StatusOK {
 		return nil, fmt.Errorf("http status code:%d, desc:%s", resp.StatusCode(), string(resp.Body()))
 	}
@@ -79,6 +108,46 @@ func (c *RestClient) do(r rest.IRequest) ([]byte, error) {
 	return resp.Body(), nil
 }
 
+func (c *RestClient) stats() Metrics {
+	if c.Metrics == nil {
+		return NopMetrics()
+	}
+	return c.Metrics
+}
+
+func (c *RestClient) log() Logger {
+	if c.Logger == nil {
+		return NopLogger()
+	}
+	return c.Logger
+}
+
+// fill in clOrdId and tag of order params, including every element of a batch.
+// Only params implementing rest.ITaggedParam or rest.IOrderParam are stamped:
+// OKX takes no clOrdId or tag on signal bot orders (signal.PlaceSubOrderParam,
+// signal.PlaceOrderAlgoParam) and no clOrdId on copy trading orders.
+func injectOrderIds(param interface{}, ids *common.ClOrdIdGenerator, tag string) {
+	if param == nil {
+		return
+	}
+	v := reflect.ValueOf(param)
+	if v.Kind() == reflect.Slice {
+		for i := 0; i < v.Len(); i++ {
+			if item := v.Index(i); item.CanAddr() {
+				injectOrderIds(item.Addr().Interface(), ids, tag)
+			}
+		}
+		return
+	}
+
+	if p, ok := param.(rest.IOrderParam); ok && p.GetClOrdId() == "" && ids != nil {
+		p.SetClOrdId(ids.Next())
+	}
+	if p, ok := param.(rest.ITaggedParam); ok && p.GetTag() == "" && tag != "" {
+		p.SetTag(tag)
+	}
+}
+
 // new *fasthttp.Request
 func (c *RestClient) newRequest(r rest.IRequest) *fasthttp.Request {
 	req := fasthttp.AcquireRequest()
@@ -0,0 +1,327 @@
+package okx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/ws"
+)
+
+// AckTimeout bounds the wait for an acknowledgement when the context has no
+// deadline of its own.
+const AckTimeout = 10 * time.Second
+
+var ErrAckTimeout = errors.New("timed out waiting for acknowledgement")
+
+// pendingAck is an op waiting for OKX to acknowledge each of its args. args
+// holds those not acknowledged yet.
+type pendingAck struct {
+	op   string
+	args map[ws.Args]bool
+	done chan error
+}
+
+func (p *pendingAck) finish(err error) {
+	select {
+	case p.done <- err:
+	default:
+	}
+}
+
+// SubscribeAndWait subscribes and blocks until OKX acknowledges every arg. A
+// rejection is returned as a ws.OKXError and a missing acknowledgement as
+// ErrAckTimeout. Args OKX may still apply after any other error, such as
+// those already sent when ctx ended, are tracked like a Subscribe.
+func (client *WSClient) SubscribeAndWait(ctx context.Context, args []ws.Args) error {
+	if client.endpointType == "private" {
+		if err := client.LoginContext(ctx); err != nil {
+			return err
+		}
+	}
+	sent, err := client.sendAndWait(ctx, "subscribe", args)
+	client.track(args[:sent], false)
+	return err
+}
+
+// UnsubscribeAndWait unsubscribes and blocks until OKX acknowledges every
+// arg, with the same errors as SubscribeAndWait.
+func (client *WSClient) UnsubscribeAndWait(ctx context.Context, args []ws.Args) error {
+	if client.endpointType == "private" {
+		if err := client.LoginContext(ctx); err != nil {
+			return err
+		}
+	}
+	sent, err := client.sendAndWait(ctx, "unsubscribe", args)
+	client.untrack(args[:sent])
+	return err
+}
+
+// sendSubscribe tracks args and subscribes them without waiting. Their
+// request id is remembered until OKX acknowledges every arg, so a rejected
+// subscription is untracked instead of being replayed on every reconnect.
+func (client *WSClient) sendSubscribe(args interface{}, auth bool) error {
+	added := client.track(args, auth)
+	request := ws.NewRequestSubscribe(args)
+	if requestArgs, ok := args.([]ws.Args); ok {
+		client.expectAcks(request, requestArgs)
+	}
+	sent, err := client.sendRequest(client.ctx, request)
+	if err != nil {
+		// the args already written are subscribed all the same
+		requestArgs, _ := args.([]ws.Args)
+		client.forgetAcks(request.Id)
+		client.rememberAcks(request.Id, requestArgs[:sent])
+		client.untrack(unsent(added, requestArgs[:sent]))
+		return err
+	}
+	return nil
+}
+
+// unsent returns the args of added that are not among sent.
+func unsent(added, sent []ws.Args) []ws.Args {
+	written := make(map[ws.Args]bool, len(sent))
+	for _, arg := range sent {
+		written[arg] = true
+	}
+	var rest []ws.Args
+	for _, arg := range added {
+		if !written[arg] {
+			rest = append(rest, arg)
+		}
+	}
+	return rest
+}
+
+// expectAcks gives request an id and remembers its args until they are
+// acknowledged.
+func (client *WSClient) expectAcks(request *ws.Request, args []ws.Args) {
+	if len(args) == 0 {
+		return
+	}
+	request.Id = client.nextRequestId()
+	client.rememberAcks(request.Id, args)
+}
+
+// rememberAcks keeps the args subscribed by request id until they are
+// acknowledged, so that a rejection untracks them.
+func (client *WSClient) rememberAcks(id string, args []ws.Args) {
+	if len(args) == 0 {
+		return
+	}
+	expected := make(map[ws.Args]bool, len(args))
+	for _, arg := range args {
+		expected[arg] = true
+	}
+	client.ackMu.Lock()
+	client.unacked[id] = expected
+	client.ackMu.Unlock()
+}
+
+func (client *WSClient) forgetAcks(id string) {
+	client.ackMu.Lock()
+	delete(client.unacked, id)
+	client.ackMu.Unlock()
+}
+
+// settleSubscribe clears the acknowledged args of subscribes sent without
+// waiting and returns the args of one OKX rejected.
+func (client *WSClient) settleSubscribe(event *ws.Event) []ws.Args {
+	client.ackMu.Lock()
+	defer client.ackMu.Unlock()
+
+	expected, ok := client.unacked[event.Id]
+	if event.IsError() {
+		if !ok {
+			if event.Arg != nil {
+				return []ws.Args{*event.Arg}
+			}
+			return nil
+		}
+		delete(client.unacked, event.Id)
+		rejected := make([]ws.Args, 0, len(expected))
+		for arg := range expected {
+			rejected = append(rejected, arg)
+		}
+		return rejected
+	}
+	if ok && event.Event == "subscribe" && event.Arg != nil {
+		delete(expected, *event.Arg)
+		if len(expected) == 0 {
+			delete(client.unacked, event.Id)
+		}
+	}
+	return nil
+}
+
+func (client *WSClient) nextRequestId() string {
+	return strconv.FormatUint(atomic.AddUint64(&client.requestId, 1), 10)
+}
+
+// sendAndWait sends the op with a fresh request id and waits for it to be
+// acknowledged. It returns the number of leading args OKX has applied or may
+// still apply: all of them unless sending failed partway or OKX rejected the
+// op. Subscribed args whose acknowledgement is still due are remembered, so
+// that a late rejection untracks them.
+func (client *WSClient) sendAndWait(ctx context.Context, op string, args []ws.Args) (int, error) {
+	if len(args) == 0 {
+		return 0, errors.New("invalid args: must be non-empty []ws.Args")
+	}
+
+	id := client.nextRequestId()
+	pending := &pendingAck{
+		op:   op,
+		args: make(map[ws.Args]bool, len(args)),
+		done: make(chan error, 1),
+	}
+	for _, arg := range args {
+		pending.args[arg] = true
+	}
+
+	client.ackMu.Lock()
+	client.pendingAcks[id] = pending
+	client.ackMu.Unlock()
+	defer func() {
+		client.ackMu.Lock()
+		delete(client.pendingAcks, id)
+		client.ackMu.Unlock()
+	}()
+
+	if sent, err := client.sendRequest(ctx, &ws.Request{Id: id, Op: op, Args: args}); err != nil {
+		if op == "subscribe" {
+			client.rememberAcks(id, args[:sent])
+		}
+		return sent, err
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, AckTimeout)
+		defer cancel()
+	}
+	var err error
+	select {
+	case err = <-pending.done:
+		var okxErr ws.OKXError
+		if errors.As(err, &okxErr) {
+			return 0, err
+		}
+	case <-ctx.Done():
+		err = ctx.Err()
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = fmt.Errorf("%s %v: %w", op, args, ErrAckTimeout)
+		}
+	}
+	if err != nil && op == "subscribe" {
+		client.ackMu.Lock()
+		due := make([]ws.Args, 0, len(pending.args))
+		for arg := range pending.args {
+			due = append(due, arg)
+		}
+		client.ackMu.Unlock()
+		client.rememberAcks(id, due)
+	}
+	return len(args), err
+}
+
+// resolveAck matches a subscribe, unsubscribe or error event with the op it
+// answers, by request id or, for replies without one, by arg.
+func (client *WSClient) resolveAck(event *ws.Event) {
+	if rejected := client.settleSubscribe(event); len(rejected) > 0 {
+		client.log().Warn("subscription rejected", "endpoint", client.endpointType, "args", rejected, "code", event.Code, "msg", event.Msg)
+		client.untrack(rejected)
+	}
+
+	client.ackMu.Lock()
+	defer client.ackMu.Unlock()
+
+	pending, ok := client.pendingAcks[event.Id]
+	if !ok && event.Id == "" {
+		pending = client.pendingByArg(event)
+	}
+	if pending == nil {
+		return
+	}
+
+	if event.IsError() {
+		pending.finish(ws.NewOKXError(event.Code, event.Msg))
+		return
+	}
+	if event.Event != pending.op {
+		return
+	}
+	// duplicate acks and acks of args of another op do not count
+	if event.Arg == nil || !pending.args[*event.Arg] {
+		return
+	}
+	delete(pending.args, *event.Arg)
+	if len(pending.args) == 0 {
+		pending.finish(nil)
+	}
+}
+
+// pendingByArg finds the op an id-less reply belongs to. Error events carry
+// no arg, so they are only attributed when a single op is pending. The caller
+// must hold ackMu.
+func (client *WSClient) pendingByArg(event *ws.Event) *pendingAck {
+	if event.Arg == nil {
+		if event.IsError() && len(client.pendingAcks) == 1 {
+			for _, pending := range client.pendingAcks {
+				return pending
+			}
+		}
+		return nil
+	}
+	for _, pending := range client.pendingAcks {
+		if pending.op == event.Event && pending.args[*event.Arg] {
+			return pending
+		}
+	}
+	return nil
+}
+
+// failPending aborts every op still waiting, e.g. when the connection drops.
+func (client *WSClient) failPending(err error) {
+	client.ackMu.Lock()
+	defer client.ackMu.Unlock()
+	for _, pending := range client.pendingAcks {
+		pending.finish(err)
+	}
+	for id := range client.unacked {
+		delete(client.unacked, id)
+	}
+	for id, future := range client.pendingOps {
+		delete(client.pendingOps, id)
+		future.complete(nil, err)
+	}
+}
+
+// SubscribeAndWait routes args to their endpoints like Subscribe and waits
+// for every endpoint to acknowledge them.
+func (c *OKXWsClient) SubscribeAndWait(ctx context.Context, args []ws.Args) error {
+	return c.routeAndWait(ctx, "subscribe", args)
+}
+
+func (c *OKXWsClient) UnsubscribeAndWait(ctx context.Context, args []ws.Args) error {
+	return c.routeAndWait(ctx, "unsubscribe", args)
+}
+
+func (c *OKXWsClient) routeAndWait(ctx context.Context, op string, args []ws.Args) error {
+	if len(args) == 0 {
+		return errors.New("invalid subscription args: must be non-empty []ws.Args")
+	}
+	groups, err := partitionArgs(args)
+	if err != nil {
+		return err
+	}
+
+	for _, group := range groups {
+		if err := c.pools[group.endpoint].sendAndWait(ctx, op, group.args, group.auth); err != nil {
+			return fmt.Errorf("%s %s failed: %w", group, op, err)
+		}
+	}
+	return nil
+}
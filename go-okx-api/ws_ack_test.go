@@ -0,0 +1,77 @@
+package okx
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/ws"
+)
+
+func TestSubscribeAndWait(t *testing.T) {
+	f := newFakeOKX(t)
+	f.Reject("nope")
+	client := newTestClient(t, f)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	good := []ws.Args{{Channel: "tickers", InstId: "BTC-USDT"}, {Channel: "tickers", InstId: "ETH-USDT"}}
+	if err := client.SubscribeAndWait(ctx, good); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(client.Subscriptions()); n != 2 {
+		t.Fatalf("%d subscriptions tracked, want 2", n)
+	}
+
+	err := client.SubscribeAndWait(ctx, []ws.Args{{Channel: "nope", InstId: "BTC-USDT"}})
+	var okxErr ws.OKXError
+	if !errors.As(err, &okxErr) || okxErr.Code != "60018" {
+		t.Fatalf("got %v, want the rejection", err)
+	}
+	if n := len(client.Subscriptions()); n != 2 {
+		t.Fatalf("%d subscriptions tracked after a rejection, want 2", n)
+	}
+
+	if err := client.UnsubscribeAndWait(ctx, good[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if subs := client.Subscriptions(); len(subs) != 1 || subs[0] != good[1] {
+		t.Fatalf("tracked %v after unsubscribing, want %v", subs, good[1:])
+	}
+}
+
+func TestResolveAckIgnoresDuplicates(t *testing.T) {
+	client := &WSClient{
+		pendingAcks: make(map[string]*pendingAck),
+		unacked:     make(map[string]map[ws.Args]bool),
+	}
+	a := ws.Args{Channel: "tickers", InstId: "BTC-USDT"}
+	b := ws.Args{Channel: "tickers", InstId: "ETH-USDT"}
+	other := ws.Args{Channel: "tickers", InstId: "SOL-USDT"}
+	pending := &pendingAck{
+		op:   "subscribe",
+		args: map[ws.Args]bool{a: true, b: true},
+		done: make(chan error, 1),
+	}
+	client.pendingAcks["1"] = pending
+
+	client.resolveAck(&ws.Event{Event: "subscribe", Id: "1", Arg: &a})
+	client.resolveAck(&ws.Event{Event: "subscribe", Id: "1", Arg: &a})
+	client.resolveAck(&ws.Event{Event: "subscribe", Id: "1", Arg: &other})
+	select {
+	case err := <-pending.done:
+		t.Fatalf("finished early with %v", err)
+	default:
+	}
+
+	client.resolveAck(&ws.Event{Event: "subscribe", Id: "1", Arg: &b})
+	select {
+	case err := <-pending.done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	default:
+		t.Fatal("not finished once every arg was acknowledged")
+	}
+}
@@ -3,47 +3,108 @@ package okx
 import (
 	"cadenza-market-connector-okx/pkg/go-okx-api/common"
 	"cadenza-market-connector-okx/pkg/go-okx-api/models/ws"
-	"cadenza-market-connector-okx/pkg/go-okx-api/models/ws/business"
-	"cadenza-market-connector-okx/pkg/go-okx-api/models/ws/public"
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/ws/private"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
 	"net/url"
-	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/chuckpreslar/emission"
 	"github.com/gorilla/websocket"
 )
 
 const (
 	MaxReconnectAttempts = 5
 	ConnectTimeout       = 10 * time.Second
+	LoginTimeout         = 10 * time.Second
 )
 
+var ErrLoginTimeout = errors.New("timed out waiting for login")
+
+// loginAttempt is a login op waiting for its reply. done is closed once err
+// is set. timeout fails the attempt if no reply comes within LoginTimeout,
+// and cancel drops the login request if it is still waiting to be sent.
+type loginAttempt struct {
+	done    chan struct{}
+	err     error
+	timeout *time.Timer
+	cancel  context.CancelFunc
+}
+
+// EventMmpTriggered is emitted on the private client with the *private.Order
+// of every order cancelled because market maker protection was triggered.
+const EventMmpTriggered = "mmp_triggered"
+
 type OKXWsClient struct {
 	Public   *WSClient
 	Private  *WSClient
 	Business *WSClient
+	// SubscriptionQueue configures the queue behind each channel returned by
+	// Subscribe and the typed Subscribe methods. The zero value holds
+	// DefaultQueueSize events per arg and then blocks the connection until
+	// the channel is read.
+	SubscriptionQueue QueueConfig
+	// holders counts the Subscriptions of each arg, so one ending does not
+	// unsubscribe the others.
+	holdMu  sync.Mutex
+	holders map[ws.Args]int
+	// pools shard subscriptions of each endpoint over several connections,
+	// the first being Public, Private or Business.
+	pools map[string]*ConnectionPool
 }
 
 type WSClient struct {
-	auth         common.Auth
-	conn         *websocket.Conn
+	// requestId is first to stay 64-bit aligned for atomic access.
+	requestId uint64
+	auth      common.Auth
+	conn      *websocket.Conn
+	// ctx lives until Close, connCancel only until the current connection drops.
 	ctx          context.Context
 	cancel       context.CancelFunc
+	connCancel   context.CancelFunc
 	endpointType string // "public", "private", or "business"
 	debugMode    bool
 	endpoint     string
 	pingInterval time.Duration
-	lastResponse time.Time
-	emitter      *emission.Emitter
+	bus          *eventBus
 	mu           sync.Mutex
-	connected    bool
-	reconnecting bool
+	state        ConnectionState
+	login        *loginAttempt
+	hooks        stateHooks
+	ackMu        sync.Mutex
+	pendingAcks  map[string]*pendingAck
+	pendingOps   map[string]*OpFuture
+	// unacked holds the args of subscribes sent without waiting, by request
+	// id, until OKX acknowledges them.
+	unacked map[string]map[ws.Args]bool
+	// Tag and ClOrdIds are filled into orders placed over the websocket, like
+	// on the RestClient.
+	Tag      string
+	ClOrdIds *common.ClOrdIdGenerator
+	// reconnectPolicy is nil when automatic reconnection is disabled.
+	reconnectPolicy *ReconnectPolicy
+	// throttle paces outbound requests, nil when they are written at once.
+	throttle *throttle
+	logger   loggerRef
+	// verbose logs every message received at debug level.
+	verbose atomic.Bool
+	metrics metricsRef
+	// watchdog is nil while streams are not watched for silence.
+	watchdog atomic.Pointer[WatchdogConfig]
+	// streamMu guards lastUpdate, the time of the last push of every
+	// subscribed arg on the current connection, and recoveries, the
+	// resubscribes of every arg that have not brought a push since.
+	streamMu   sync.Mutex
+	lastUpdate map[ws.Args]time.Time
+	recoveries map[ws.Args]int
+	// pingSent is the UnixNano time of the ping awaiting its pong, 0 if none.
+	pingSent atomic.Int64
+	// subscriptions holds every channel subscribed on this connection, mapped
+	// to whether it needs a login, so they can be replayed after a reconnect.
+	subscriptions map[ws.Args]bool
 }
 
 func NewOKXWsClient(auth common.Auth) *OKXWsClient {
@@ -55,28 +116,48 @@ func NewOKXWsClient(auth common.Auth) *OKXWsClient {
 		Public:   public,
 		Private:  private,
 		Business: business,
+		pools: map[string]*ConnectionPool{
+			EndpointPublic:   newConnectionPool(public),
+			EndpointPrivate:  newConnectionPool(private),
+			EndpointBusiness: newConnectionPool(business),
+		},
 	}
 }
 
 func NewWSClient(endpointType string, auth common.Auth) *WSClient {
+	return newWSClient(endpointType, determineEndpoint(endpointType, auth.DebugMode), auth, newEventBus(), DefaultReconnectPolicy(), DefaultThrottleConfig(), NopLogger())
+}
+
+// newWSClient connects a client that publishes its events on bus, which
+// connections of the same pool share.
+func newWSClient(endpointType, endpoint string, auth common.Auth, bus *eventBus, policy *ReconnectPolicy, throttleConfig *ThrottleConfig, logger Logger) *WSClient {
 	ctx, cancel := context.WithCancel(context.Background())
-	endpoint := determineEndpoint(endpointType, auth.DebugMode)
 
 	client := &WSClient{
-		auth:         auth,
-		ctx:          ctx,
-		cancel:       cancel,
-		endpointType: endpointType,
-		debugMode:    auth.DebugMode,
-		endpoint:     endpoint,
-		pingInterval: 20 * time.Second,
-		emitter:      emission.NewEmitter(),
-		connected:    false,
-		reconnecting: false,
-	}
+		auth:            auth,
+		ctx:             ctx,
+		cancel:          cancel,
+		endpointType:    endpointType,
+		debugMode:       auth.DebugMode,
+		endpoint:        endpoint,
+		pingInterval:    20 * time.Second,
+		bus:             bus,
+		state:           StateIdle,
+		reconnectPolicy: policy,
+		throttle:        newThrottle(throttleConfig),
+		subscriptions:   make(map[ws.Args]bool),
+		lastUpdate:      make(map[ws.Args]time.Time),
+		recoveries:      make(map[ws.Args]int),
+		pendingAcks:     make(map[string]*pendingAck),
+		pendingOps:      make(map[string]*OpFuture),
+		unacked:         make(map[string]map[ws.Args]bool),
+	}
+
+	client.logger.set(logger)
+	bus.logger.set(logger)
 
 	if err := client.Connect(); err != nil {
-		log.Printf("Initial connection failed for %s: %v", endpointType, err)
+		client.log().Warn("initial connection failed", "endpoint", endpointType, "error", err)
 	}
 
 	return client
@@ -110,51 +191,15 @@ func (c *OKXWsClient) Subscribe(args interface{}) error {
 		return errors.New("invalid subscription args: must be non-empty []ws.Args")
 	}
 
-	publicArgs := []ws.Args{}
-	privateArgs := []ws.Args{}
-	businessNoAuthArgs := []ws.Args{}
-	businessAuthArgs := []ws.Args{}
-
-	for _, arg := range requestArgs {
-		channel := arg.Channel
-		if isPrivateChannel(channel) {
-			privateArgs = append(privateArgs, arg)
-		} else if isBusinessChannel(channel) {
-			if requiresBusinessAuth(channel) {
-				businessAuthArgs = append(businessAuthArgs, arg)
-			} else {
-				businessNoAuthArgs = append(businessNoAuthArgs, arg)
-			}
-		} else {
-			publicArgs = append(publicArgs, arg)
-		}
+	groups, err := partitionArgs(requestArgs)
+	if err != nil {
+		return err
 	}
 
-	fmt.Printf(" publicArgs %v\n", publicArgs)
-	fmt.Printf(" privateArgs %v\n", privateArgs)
-	fmt.Printf(" businessNoAuthArgs %v\n", businessNoAuthArgs)
-	fmt.Printf(" businessAuthArgs %v\n", businessAuthArgs)
-
 	var errs []error
-	if len(publicArgs) > 0 {
-		if err := c.Public.Subscribe(publicArgs); err != nil {
-			errs = append(errs, fmt.Errorf("public subscribe failed: %v", err))
-		}
-	}
-	if len(privateArgs) > 0 {
-		if err := c.Private.Subscribe(privateArgs); err != nil {
-			errs = append(errs, fmt.Errorf("private subscribe failed: %v", err))
-		}
-	}
-	if len(businessNoAuthArgs) > 0 {
-		if err := c.Business.Subscribe(businessNoAuthArgs); err != nil {
-			errs = append(errs, fmt.Errorf("business no-auth subscribe failed: %v", err))
-		}
-	}
-	if len(businessAuthArgs) > 0 {
-		fmt.Println("code arrives here in businessAuthArgs")
-		if err := c.Business.SubscribeWithAuth(businessAuthArgs); err != nil {
-			errs = append(errs, fmt.Errorf("business with-auth subscribe failed: %v", err))
+	for _, group := range groups {
+		if err := c.pools[group.endpoint].subscribe(group.args, group.auth); err != nil {
+			errs = append(errs, fmt.Errorf("%s subscribe failed: %v", group, err))
 		}
 	}
 
@@ -171,110 +216,465 @@ func (c *OKXWsClient) Subscribe(args interface{}) error {
 	return nil
 }
 
+// argGroup is the args of a request served by one endpoint.
+type argGroup struct {
+	endpoint string
+	auth     bool
+	args     []ws.Args
+}
+
+func (g argGroup) String() string {
+	switch {
+	case g.endpoint == EndpointPrivate:
+		return g.endpoint
+	case g.auth:
+		return g.endpoint + " with-auth"
+	case g.endpoint == EndpointBusiness:
+		return g.endpoint + " no-auth"
+	}
+	return g.endpoint
+}
+
+// partitionArgs splits args by the endpoint that serves them according to
+// the channel catalog, checking that each sets the keys its channel needs.
+func partitionArgs(args []ws.Args) ([]argGroup, error) {
+	type groupKey struct {
+		endpoint string
+		auth     bool
+	}
+	var groups []argGroup
+	index := make(map[groupKey]int)
+	for _, arg := range args {
+		spec, _ := Channels.Lookup(arg.Channel)
+		if err := spec.Validate(arg); err != nil {
+			return nil, err
+		}
+		// the private client logs in anyway, auth only matters elsewhere
+		key := groupKey{endpoint: spec.Endpoint, auth: spec.NeedsAuth() && spec.Endpoint != EndpointPrivate}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, argGroup{endpoint: key.endpoint, auth: key.auth})
+		}
+		groups[i].args = append(groups[i].args, arg)
+	}
+	return groups, nil
+}
+
+// SetReconnectPolicy applies the policy to the public, private and business
+// clients. A nil policy disables automatic reconnection.
+func (c *OKXWsClient) SetReconnectPolicy(policy *ReconnectPolicy) {
+	for _, pool := range c.pools {
+		pool.SetReconnectPolicy(policy)
+	}
+}
+
+// SetReconnectPolicy replaces the policy used after the connection drops. A
+// nil policy disables automatic reconnection.
+func (client *WSClient) SetReconnectPolicy(policy *ReconnectPolicy) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	client.reconnectPolicy = policy
+}
+
 func (client *WSClient) SubscribeWithAuth(args interface{}) error {
 	if err := client.Login(); err != nil {
 		return err
 	}
-	return client.sendRequest(ws.NewRequestSubscribe(args))
+	return client.sendSubscribe(args, true)
 }
 
+// Connect dials the endpoint from the Idle or Reconnecting state. The lock is
+// not held while dialing, the Connecting state keeps other callers out.
 func (client *WSClient) Connect() error {
 	client.mu.Lock()
-	defer client.mu.Unlock()
-
-	u, err := url.Parse(client.endpoint)
-	if err != nil {
-		return err
+	from := client.state
+	if from != StateIdle && from != StateReconnecting {
+		client.mu.Unlock()
+		return fmt.Errorf("cannot connect %s client while %s", client.endpointType, from)
 	}
+	change := client.setState(StateConnecting, nil)
+	client.mu.Unlock()
+	client.publish(change)
 
-	c, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	c, err := client.dial()
+
+	client.mu.Lock()
+	if err == nil && client.state != StateConnecting {
+		c.Close()
+		err = fmt.Errorf("%s client %s while connecting", client.endpointType, client.state)
+	}
 	if err != nil {
+		change = nil
+		if client.state == StateConnecting {
+			change = client.setState(from, err)
+		}
+		client.mu.Unlock()
+		client.publish(change)
 		return err
 	}
+
+	ctx, cancel := context.WithCancel(client.ctx)
 	client.conn = c
-	client.lastResponse = time.Now()
-	client.connected = true
-	client.reconnecting = false
+	client.connCancel = cancel
+	if client.throttle != nil {
+		client.throttle.reset()
+	}
+	change = client.setState(StateConnected, nil)
+	client.mu.Unlock()
+	client.publish(change)
+	client.resetStreams()
 
-	go client.handleMessages()
-	go client.manageHeartbeat()
+	go client.handleMessages(ctx, c)
+	go client.manageHeartbeat(ctx, c)
+	go client.watchStreams(ctx, c)
 
 	return nil
 }
 
-func (client *WSClient) manageHeartbeat() {
+func (client *WSClient) dial() (*websocket.Conn, error) {
+	u, err := url.Parse(client.endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := *websocket.DefaultDialer
+	dialer.HandshakeTimeout = ConnectTimeout
+	c, _, err := dialer.Dial(u.String(), nil)
+	return c, err
+}
+
+func (client *WSClient) manageHeartbeat(ctx context.Context, conn *websocket.Conn) {
 	ticker := time.NewTicker(client.pingInterval)
 	defer ticker.Stop()
 	for {
 		select {
 		case <-ticker.C:
 			if err := client.sendPing(); err != nil {
-				log.Printf("Ping failed for %s: %v", client.endpointType, err)
-				client.reconnect()
+				client.log().Warn("ping failed", "endpoint", client.endpointType, "error", err)
+				client.connectionLost(conn, err)
+				return
 			}
-		case <-client.ctx.Done():
+		case <-ctx.Done():
 			return
 		}
 	}
 }
 
 func (client *WSClient) sendPing() error {
-	return client.sendTextMessage("ping")
+	client.pingSent.Store(time.Now().UnixNano())
+	return client.write([]byte("ping"))
 }
 
-func (client *WSClient) sendTextMessage(message string) error {
+// write sends a text frame on the open connection. Writes are serialised by
+// mu as the websocket allows a single concurrent writer.
+func (client *WSClient) write(data []byte) error {
 	client.mu.Lock()
-	defer client.mu.Unlock()
-
-	if !client.connected {
+	if !client.state.IsOpen() {
+		client.mu.Unlock()
 		return errors.New("client not connected")
 	}
+	conn := client.conn
+	err := conn.WriteMessage(websocket.TextMessage, data)
+	client.mu.Unlock()
 
-	if err := client.conn.WriteMessage(websocket.TextMessage, []byte(message)); err != nil {
-		client.connected = false
+	if err != nil {
+		client.connectionLost(conn, err)
 		return err
 	}
 	return nil
 }
 
-func (client *WSClient) reconnect() {
+// connectionLost tears down conn and, if a reconnect policy is set, starts
+// reconnecting. It ignores connections that were already replaced.
+func (client *WSClient) connectionLost(conn *websocket.Conn, err error) {
 	client.mu.Lock()
-	defer client.mu.Unlock()
+	if conn != client.conn || !client.state.IsOpen() {
+		client.mu.Unlock()
+		return
+	}
+	client.dropConnection()
+	policy := client.reconnectPolicy
+	next := StateIdle
+	if policy != nil {
+		next = StateReconnecting
+	}
+	change := client.setState(next, err)
+	client.mu.Unlock()
+	client.publish(change)
+	client.failPending(fmt.Errorf("connection lost: %v", err))
 
-	client.cancel()
-	ctx, cancel := context.WithCancel(context.Background())
-	client.ctx = ctx
-	client.cancel = cancel
-
-	attempts := 0
-	for attempts < MaxReconnectAttempts {
-		if err := client.Connect(); err != nil {
-			attempts++
-			if attempts == MaxReconnectAttempts {
-				log.Printf("Max reconnection attempts reached for %s: %v", client.endpointType, err)
+	if policy == nil {
+		client.log().Warn("connection lost, automatic reconnect disabled", "endpoint", client.endpointType, "error", err)
+		return
+	}
+	go client.reconnect(policy)
+}
+
+// finishLogin releases everyone waiting on the pending login. The caller
+// must hold mu.
+func (client *WSClient) finishLogin(err error) {
+	if client.login == nil {
+		return
+	}
+	client.login.timeout.Stop()
+	if client.login.cancel != nil {
+		client.login.cancel()
+	}
+	client.login.err = err
+	close(client.login.done)
+	client.login = nil
+}
+
+// dropConnection stops the goroutines of the current connection and closes
+// it. The caller must hold mu.
+func (client *WSClient) dropConnection() error {
+	client.finishLogin(errors.New("connection lost"))
+	if client.connCancel != nil {
+		client.connCancel()
+		client.connCancel = nil
+	}
+	if client.conn == nil {
+		return nil
+	}
+	err := client.conn.Close()
+	client.conn = nil
+	return err
+}
+
+// reconnect re-dials the endpoint following the policy and replays every
+// subscription. It runs from the Reconnecting state until it succeeds, gives
+// up or the client is closed.
+func (client *WSClient) reconnect(policy *ReconnectPolicy) {
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-time.After(policy.Delay(attempt)):
+		case <-client.ctx.Done():
+			return
+		}
+
+		err := client.Connect()
+		if err == nil {
+			if err = client.resubscribe(); err == nil {
+				client.log().Info("reconnected", "endpoint", client.endpointType, "attempts", attempt)
+				client.stats().Reconnected(client.endpointType)
+				client.fireReconnected()
 				return
 			}
-			time.Sleep(2 * time.Second)
-			continue
-		}
+			err = fmt.Errorf("resubscribe: %v", err)
 
-		client.connected = true
-		client.reconnecting = false
+			client.mu.Lock()
+			if !client.state.IsOpen() {
+				// the connection was lost meanwhile and another reconnect took over
+				client.mu.Unlock()
+				return
+			}
+			client.dropConnection()
+			change := client.setState(StateReconnecting, err)
+			client.mu.Unlock()
+			client.publish(change)
+		}
 
-		if client.endpointType == "private" {
-			if err := client.Login(); err != nil {
-				log.Printf("Login after reconnect failed for %s: %v", client.endpointType, err)
-				continue
+		client.log().Warn("reconnect attempt failed", "endpoint", client.endpointType, "attempt", attempt, "error", err)
+		if policy.exhausted(attempt) {
+			client.log().Error("giving up reconnecting", "endpoint", client.endpointType, "attempts", attempt, "error", err)
+			client.mu.Lock()
+			var change *StateChange
+			if client.state == StateReconnecting {
+				change = client.setState(StateIdle, err)
+			}
+			client.mu.Unlock()
+			client.publish(change)
+			if policy.OnGiveUp != nil {
+				policy.OnGiveUp(client.endpointType, err)
 			}
+			return
 		}
-		log.Printf("Successfully reconnected after %d attempts for %s", attempts+1, client.endpointType)
+	}
+}
+
+// resubscribe logs in where required and re-sends every tracked
+// subscription on the current connection.
+func (client *WSClient) resubscribe() error {
+	client.mu.Lock()
+	needsLogin := client.endpointType == "private"
+	args := make([]ws.Args, 0, len(client.subscriptions))
+	for arg, auth := range client.subscriptions {
+		args = append(args, arg)
+		needsLogin = needsLogin || auth
+	}
+	client.mu.Unlock()
+
+	if needsLogin {
+		if err := client.Login(); err != nil {
+			return fmt.Errorf("login: %v", err)
+		}
+	}
+	if len(args) == 0 {
+		return nil
+	}
+	request := ws.NewRequestSubscribe(args)
+	client.expectAcks(request, args)
+	_, err := client.sendRequest(client.ctx, request)
+	return err
+}
+
+// track records subscribed args in the registry replayed on reconnect and
+// returns those that were not tracked yet.
+func (client *WSClient) track(args interface{}, auth bool) []ws.Args {
+	requestArgs, ok := args.([]ws.Args)
+	if !ok {
+		return nil
+	}
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	var added []ws.Args
+	for _, arg := range requestArgs {
+		tracked, ok := client.subscriptions[arg]
+		if !ok {
+			added = append(added, arg)
+		}
+		client.subscriptions[arg] = auth || tracked
+	}
+	return added
+}
+
+func (client *WSClient) untrack(args interface{}) {
+	requestArgs, ok := args.([]ws.Args)
+	if !ok {
 		return
 	}
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	for _, arg := range requestArgs {
+		delete(client.subscriptions, arg)
+	}
 }
 
+// trackedSubscriptions returns every tracked arg mapped to whether it needs a
+// login.
+func (client *WSClient) trackedSubscriptions() map[ws.Args]bool {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	subs := make(map[ws.Args]bool, len(client.subscriptions))
+	for arg, auth := range client.subscriptions {
+		subs[arg] = auth
+	}
+	return subs
+}
+
+func (client *WSClient) subscriptionCount() int {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	return len(client.subscriptions)
+}
+
+func (client *WSClient) isSubscribed(arg ws.Args) bool {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	_, ok := client.subscriptions[arg]
+	return ok
+}
+
+// Subscriptions returns the channels that are replayed after a reconnect.
+func (client *WSClient) Subscriptions() []ws.Args {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	args := make([]ws.Args, 0, len(client.subscriptions))
+	for arg := range client.subscriptions {
+		args = append(args, arg)
+	}
+	return args
+}
+
+// Login logs in and waits up to LoginTimeout for OKX to accept it.
 func (client *WSClient) Login() error {
-	loginArgs := ws.NewRequestLogin(client.auth)
-	return client.sendRequest(loginArgs)
+	return client.LoginContext(context.Background())
+}
+
+// LoginContext logs in and waits for the login event. A login that is
+// already in flight is joined rather than sent again. Rejections are returned
+// as ws.LoginError and a missing reply as ErrLoginTimeout. If ctx ends first
+// its error is returned, leaving the login to other callers.
+func (client *WSClient) LoginContext(ctx context.Context) error {
+	client.mu.Lock()
+	switch client.state {
+	case StateAuthenticated:
+		client.mu.Unlock()
+		return nil
+	case StateLoggingIn:
+		attempt := client.login
+		client.mu.Unlock()
+		return client.awaitLogin(ctx, attempt)
+	case StateConnected:
+	default:
+		client.mu.Unlock()
+		return errors.New("client not connected")
+	}
+	// the request lives as long as the attempt, so it cannot sit in the
+	// throttle past LoginTimeout and be sent after the attempt failed
+	sendCtx, cancel := context.WithCancel(client.ctx)
+	attempt := &loginAttempt{done: make(chan struct{}), cancel: cancel}
+	attempt.timeout = time.AfterFunc(LoginTimeout, func() {
+		client.loginResult(attempt, ErrLoginTimeout)
+	})
+	client.login = attempt
+	change := client.setState(StateLoggingIn, nil)
+	client.mu.Unlock()
+	client.publish(change)
+
+	// sent in the background, so the caller's ctx also bounds a wait for
+	// the throttle
+	go func() {
+		if _, err := client.sendRequest(sendCtx, ws.NewRequestLogin(client.auth)); err != nil {
+			client.loginResult(attempt, err)
+		}
+	}()
+	return client.awaitLogin(ctx, attempt)
+}
+
+// awaitLogin waits for attempt to be settled. Giving up when ctx ends only
+// concerns this caller: the attempt, shared by everyone logging in at the
+// same time, goes on until OKX replies or its own LoginTimeout expires.
+func (client *WSClient) awaitLogin(ctx context.Context, attempt *loginAttempt) error {
+	select {
+	case <-attempt.done:
+		return attempt.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// pendingLogin returns the login attempt awaiting its reply, if any.
+func (client *WSClient) pendingLogin() *loginAttempt {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	return client.login
+}
+
+// loginResult settles attempt from the login or error event, or from a
+// failure on our side. It ignores attempts that were already settled.
+func (client *WSClient) loginResult(attempt *loginAttempt, err error) {
+	client.mu.Lock()
+	if attempt == nil || client.login != attempt || client.state != StateLoggingIn {
+		client.mu.Unlock()
+		return
+	}
+	client.finishLogin(err)
+	var change *StateChange
+	if err == nil {
+		change = client.setState(StateAuthenticated, nil)
+	} else {
+		change = client.setState(StateConnected, err)
+	}
+	client.mu.Unlock()
+	client.publish(change)
+
+	if err != nil {
+		client.log().Error("login failed", "endpoint", client.endpointType, "error", err)
+		client.fireLoginFailed(err)
+	}
 }
 
 func (client *WSClient) Subscribe(args interface{}) error {
@@ -283,7 +683,15 @@ func (client *WSClient) Subscribe(args interface{}) error {
 			return err
 		}
 	}
-	return client.sendRequest(ws.NewRequestSubscribe(args))
+	return client.sendSubscribe(args, false)
+}
+
+// subscribe subscribes args, logging in first if auth is set.
+func (client *WSClient) subscribe(args []ws.Args, auth bool) error {
+	if auth {
+		return client.SubscribeWithAuth(args)
+	}
+	return client.Subscribe(args)
 }
 
 func (client *WSClient) Unsubscribe(args interface{}) error {
@@ -292,229 +700,319 @@ func (client *WSClient) Unsubscribe(args interface{}) error {
 			return err
 		}
 	}
-	return client.sendRequest(ws.NewRequestUnsubscribe(args))
+	sent, err := client.sendRequest(client.ctx, ws.NewRequestUnsubscribe(args))
+	if err != nil {
+		if requestArgs, ok := args.([]ws.Args); ok {
+			client.untrack(requestArgs[:sent])
+		}
+		return err
+	}
+	client.untrack(args)
+	return nil
 }
 
-func (client *WSClient) sendRequest(request *ws.Request) error {
+// sendRequest writes request once the throttle lets it through, split into
+// several messages if it is too large for one. It returns ctx.Err() if ctx
+// ends while waiting for the throttle, along with the number of leading args
+// already written, which OKX acts on regardless.
+func (client *WSClient) sendRequest(ctx context.Context, request *ws.Request) (int, error) {
 	client.mu.Lock()
-	defer client.mu.Unlock()
+	throttle := client.throttle
+	client.mu.Unlock()
 
-	if !client.connected {
-		return errors.New("client not connected")
+	maxSize := 0
+	if throttle != nil {
+		maxSize = throttle.config.MaxMessageSize
 	}
-
-	data, err := json.Marshal(request)
+	messages, err := encodeRequest(request, maxSize)
 	if err != nil {
-		return fmt.Errorf("marshalling request: %v", err)
+		return 0, fmt.Errorf("marshalling request: %v", err)
 	}
-	if err := client.conn.WriteMessage(websocket.TextMessage, data); err != nil {
-		client.connected = false
-		return fmt.Errorf("sending request: %v", err)
+	sent := 0
+	for _, message := range messages {
+		if throttle != nil {
+			if err := throttle.acquire(ctx, request.Op); err != nil {
+				return sent, err
+			}
+		}
+		if err := client.write(message.data); err != nil {
+			return sent, fmt.Errorf("sending request: %v", err)
+		}
+		sent += message.args
 	}
-	return nil
+	return sent, nil
 }
 
-func (client *WSClient) handleMessages() {
-	defer client.conn.Close()
+func (client *WSClient) handleMessages(ctx context.Context, conn *websocket.Conn) {
+	defer conn.Close()
 	for {
 		select {
-		case <-client.ctx.Done():
+		case <-ctx.Done():
 			return
 		default:
-			client.conn.SetReadDeadline(time.Now().Add(60 * time.Second))
-			_, message, err := client.conn.ReadMessage()
+			conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+			_, message, err := conn.ReadMessage()
 			if err != nil {
-				log.Printf("Read error for %s: %v", client.endpointType, err)
-				client.reconnect()
+				if ctx.Err() != nil {
+					return
+				}
+				client.log().Warn("read failed", "endpoint", client.endpointType, "error", err)
+				client.connectionLost(conn, err)
 				return
 			}
 
-			client.lastResponse = time.Now()
+			received := time.Now()
 
-			if string(message) == "ping" || string(message) == "pong" {
+			if string(message) == "pong" {
+				if sent := client.pingSent.Swap(0); sent > 0 {
+					client.stats().PingRTT(client.endpointType, received.Sub(time.Unix(0, sent)))
+				}
+				continue
+			}
+			if string(message) == "ping" {
 				continue
 			}
 
 			var response map[string]interface{}
 			if err := json.Unmarshal(message, &response); err != nil {
-				log.Printf("Failed to unmarshal response for %s: %v, message: %s", client.endpointType, err, message)
+				client.log().Warn("failed to unmarshal message", "endpoint", client.endpointType, "error", err, "message", string(message))
 				client.Emit("raw_message", message)
 				continue
 			}
 
 			switch {
 			case response["event"] == "login":
-				fmt.Printf("login response: %v\n", response)
-			case response["event"] == "subscribe":
-				client.Emit("subscribe", response)
+				if code, _ := response["code"].(string); code != "" && code != "0" {
+					msg, _ := response["msg"].(string)
+					client.loginResult(client.pendingLogin(), ws.NewLoginError(code, msg))
+				} else {
+					client.loginResult(client.pendingLogin(), nil)
+				}
+			case response["event"] == "subscribe", response["event"] == "unsubscribe":
+				var event ws.Event
+				if err := json.Unmarshal(message, &event); err == nil {
+					client.resolveAck(&event)
+				}
+				client.Emit(response["event"], response)
+			case response["op"] != nil && response["id"] != nil:
+				var opResponse ws.OpResponse
+				if err := json.Unmarshal(message, &opResponse); err == nil {
+					client.resolveOp(&opResponse)
+				} else {
+					client.log().Warn("failed to unmarshal op response", "endpoint", client.endpointType, "error", err)
+				}
 			case response["event"] == "error":
+				var event ws.Event
+				if err := json.Unmarshal(message, &event); err == nil {
+					if ws.IsLoginErrorCode(event.Code) {
+						client.loginResult(client.pendingLogin(), ws.NewLoginError(event.Code, event.Msg))
+					} else {
+						client.resolveAck(&event)
+					}
+				}
 				client.Emit("error", response)
 			default:
-				if arg, ok := response["arg"].(map[string]interface{}); ok {
-					channel, channelOk := arg["channel"].(string)
-					instId, instIdOk := arg["instId"].(string)
-					if channelOk && instIdOk {
-						eventKey := ws.Args{
-							Channel: channel,
-							InstId:  instId,
-						}
-						switch channel {
-						case "tickers":
-							var ticker public.TickerEvent
-							if err := json.Unmarshal(message, &ticker); err == nil {
-								client.Emit(eventKey, &ticker)
-							} else {
-								log.Printf("Failed to unmarshal ticker: %v", err)
-								client.Emit(eventKey, message)
-							}
-						case "trades":
-							var trade public.TradeEvent
-							if err := json.Unmarshal(message, &trade); err == nil {
-								client.Emit(eventKey, &trade)
-							} else {
-								log.Printf("Failed to unmarshal trade: %v", err)
-								client.Emit(eventKey, message)
-							}
-						case "candle1m":
-							var candle business.CandleEvent
-							if err := json.Unmarshal(message, &candle); err == nil {
-								client.Emit(eventKey, &candle)
-							} else {
-								log.Printf("Failed to unmarshal candle1m: %v", err)
-								client.Emit(eventKey, message)
-							}
-						case "books5":
-							var orderBook public.OrderBookEvent
-							if err := json.Unmarshal(message, &orderBook); err == nil {
-								client.Emit(eventKey, &orderBook)
-							} else {
-								log.Printf("Failed to unmarshal books5: %v", err)
-								client.Emit(eventKey, message)
-							}
-						default:
-							client.Emit(eventKey, message)
+				var push struct {
+					Arg *ws.Args `json:"arg"`
+				}
+				if err := json.Unmarshal(message, &push); err != nil || push.Arg == nil || push.Arg.Channel == "" {
+					client.Emit("message", message)
+					break
+				}
+				eventKey := *push.Arg
+				client.stats().MessageReceived(client.endpointType, eventKey.Channel)
+				client.touch(eventKey, received)
+				spec, _ := Channels.Lookup(eventKey.Channel)
+				if spec.Decode == nil {
+					client.Emit(eventKey, message)
+					break
+				}
+				event, err := spec.Decode(message)
+				if err != nil {
+					client.stats().DecodeFailed(client.endpointType, eventKey.Channel)
+					client.log().Warn("failed to decode push", "endpoint", client.endpointType, "channel", eventKey.Channel, "instId", eventKey.InstId, "error", err)
+					client.Emit(eventKey, message)
+					break
+				}
+				recordExchangeLatency(client.stats(), eventKey.Channel, event, received)
+				client.Emit(eventKey, event)
+				if orders, ok := event.(*private.OrderEvent); ok {
+					for i := range orders.Data {
+						if orders.Data[i].IsMmpTriggered() {
+							client.Emit(EventMmpTriggered, &orders.Data[i])
 						}
-					} else {
-						log.Printf("Missing channel or instId in arg for %s: %v", client.endpointType, arg)
-						client.Emit("message", message)
 					}
-				} else {
-					client.Emit("message", message)
 				}
 			}
 
-			if client.debugMode {
-				log.Printf("Received for %s: %s", client.endpointType, message)
+			if client.verbose.Load() {
+				client.log().Debug("received", "endpoint", client.endpointType, "message", string(message))
 			}
 		}
 	}
 }
 
+// Close ends the connection for good, stopping any reconnect in progress.
 func (client *WSClient) Close() error {
 	client.mu.Lock()
-	defer client.mu.Unlock()
-
 	client.cancel()
-	if client.conn != nil {
-		err := client.conn.Close()
-		client.connected = false
-		return err
-	}
-	return nil
+	err := client.dropConnection()
+	change := client.setState(StateClosed, nil)
+	client.mu.Unlock()
+	client.publish(change)
+	client.failPending(errors.New("client closed"))
+	return err
 }
 
-func (c *OKXWsClient) On(event interface{}, listener interface{}) *emission.Emitter {
-	wsArgs, ok := event.(ws.Args)
-	if !ok {
-		log.Printf("Warning: Event must be of args type, got %T", event)
+func (c *OKXWsClient) Close() error {
+	var firstErr error
+	for _, endpoint := range []string{EndpointPublic, EndpointPrivate, EndpointBusiness} {
+		if err := c.pools[endpoint].Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
 	}
+	return firstErr
+}
 
-	if isPrivateChannel(wsArgs.Channel) {
-		return c.Private.On(event, listener)
-	} else if isBusinessChannel(wsArgs.Channel) {
-		return c.Business.On(event, listener)
-	}
-	return c.Public.On(event, listener)
+// On registers listener on the client serving the channel of event, which
+// must be a ws.Args or a Match. A Match without a channel listens on every
+// client.
+func (c *OKXWsClient) On(event interface{}, listener interface{}) (*Listener, error) {
+	return c.route(event, func(client *WSClient) (*Listener, error) {
+		return client.On(event, listener)
+	})
 }
 
-func (c *OKXWsClient) Emit(event interface{}, arguments ...interface{}) *emission.Emitter {
-	wsArgs, ok := event.(ws.Args)
-	if !ok {
-		log.Printf("Warning: Event must be of args type, got %T", event)
-	}
-	if isPrivateChannel(wsArgs.Channel) {
-		return c.Private.Emit(event, arguments...)
-	} else if isBusinessChannel(wsArgs.Channel) {
-		return c.Business.Emit(event, arguments...)
-	}
-	return c.Public.Emit(event, arguments...)
+func (c *OKXWsClient) OnQueued(event interface{}, listener interface{}, config QueueConfig) (*Listener, error) {
+	return c.route(event, func(client *WSClient) (*Listener, error) {
+		return client.OnQueued(event, listener, config)
+	})
 }
 
-func (c *OKXWsClient) Off(event interface{}, listener interface{}) *emission.Emitter {
-	wsArgs, ok := event.(ws.Args)
-	if !ok {
-		log.Printf("Warning: Event must be of args type, got %T", event)
+// route registers a listener through register on the clients serving event.
+// Topics other than a channel, such as "error", EventStale or a Match
+// without a channel, are registered on every client.
+func (c *OKXWsClient) route(event interface{}, register func(client *WSClient) (*Listener, error)) (*Listener, error) {
+	var channel string
+	switch topic := event.(type) {
+	case ws.Args:
+		channel = topic.Channel
+	case Match:
+		channel = topic.Channel
 	}
-
-	if isPrivateChannel(wsArgs.Channel) {
-		return c.Private.Off(event, listener)
-	} else if isBusinessChannel(wsArgs.Channel) {
-		return c.Business.Off(event, listener)
+	if channel != "" {
+		return register(c.clientFor(channel))
 	}
-	return c.Public.Off(event, listener)
-}
 
-func isPrivateChannel(channel string) bool {
-	privateChannels := []string{
-		"orders",
-		"account",
-		"positions",
-		"balance_and_position",
-		"order-algo",
+	first, err := register(c.Public)
+	if err != nil {
+		return nil, err
 	}
-	eventLower := strings.ToLower(channel)
-	for _, ch := range privateChannels {
-		if strings.Contains(eventLower, ch) {
-			return true
+	for _, client := range []*WSClient{c.Private, c.Business} {
+		other, err := register(client)
+		if err != nil {
+			first.Remove()
+			return nil, err
 		}
+		first.linked = append(first.linked, other)
 	}
-	return false
+	return first, nil
+}
+
+// Emit publishes on the client serving the channel of event, or on Public
+// for other topics, which reaches listeners registered through On once.
+func (c *OKXWsClient) Emit(event interface{}, argument interface{}) {
+	wsArgs, _ := event.(ws.Args)
+	c.clientFor(wsArgs.Channel).Emit(event, argument)
 }
 
-func isBusinessChannel(channel string) bool {
-	businessChannels := []string{
-		"candle1m",
+// Off removes a listener registered with On.
+func (c *OKXWsClient) Off(listener *Listener) {
+	listener.Remove()
+}
+
+// OnListenerError sets the hook that receives listener panics and events of
+// the wrong type on every client. Without one they are logged.
+func (c *OKXWsClient) OnListenerError(fn func(topic interface{}, err error)) {
+	for _, client := range []*WSClient{c.Public, c.Private, c.Business} {
+		client.OnListenerError(fn)
 	}
-	eventLower := strings.ToLower(channel)
-	for _, ch := range businessChannels {
-		if strings.Contains(eventLower, ch) {
-			return true
-		}
+}
+
+// clientFor returns the client that serves channel.
+func (c *OKXWsClient) clientFor(channel string) *WSClient {
+	spec, _ := Channels.Lookup(channel)
+	return c.clientForEndpoint(spec.Endpoint)
+}
+
+func (c *OKXWsClient) clientForEndpoint(endpoint string) *WSClient {
+	switch endpoint {
+	case EndpointPrivate:
+		return c.Private
+	case EndpointBusiness:
+		return c.Business
 	}
-	return false
+	return c.Public
 }
 
-func requiresBusinessAuth(channel string) bool {
-	privateBusinessChannels := []string{
-		// Add known private /business channels here
+// On registers listener, a func taking the event as its only argument, for
+// event. It fails if listener is not such a func.
+func (client *WSClient) On(event interface{}, listener interface{}) (*Listener, error) {
+	fn, err := reflectListener(event, listener)
+	if err != nil {
+		return nil, err
 	}
-	eventLower := strings.ToLower(channel)
-	for _, ch := range privateBusinessChannels {
-		if strings.Contains(eventLower, ch) {
-			return true
-		}
+	return client.bus.add(event, fn), nil
+}
+
+// OnQueued is On with a delivery queue, so a slow listener only delays its own
+// events as far as config's policy allows.
+func (client *WSClient) OnQueued(event interface{}, listener interface{}, config QueueConfig) (*Listener, error) {
+	fn, err := reflectListener(event, listener)
+	if err != nil {
+		return nil, err
 	}
-	return false
+	return client.bus.addQueued(event, fn, config), nil
+}
+
+// Emit delivers argument to the listeners of event. Panics are recovered and
+// reported to the listener error hook.
+func (client *WSClient) Emit(event interface{}, argument interface{}) {
+	client.bus.publish(event, argument)
+}
+
+// Off removes a listener registered with On or Listen.
+func (client *WSClient) Off(listener *Listener) {
+	listener.Remove()
+}
+
+// SetLogger replaces the logger of the client and of the listeners on its
+// events. A nil logger discards everything.
+func (client *WSClient) SetLogger(logger Logger) {
+	client.logger.set(logger)
+	client.bus.logger.set(logger)
+}
+
+// SetVerbose logs every message received at debug level, independently of
+// DebugMode, which selects simulated trading.
+func (client *WSClient) SetVerbose(verbose bool) {
+	client.verbose.Store(verbose)
+}
+
+// SetMetrics replaces the recipient of the client's measurements. A nil
+// Metrics discards them.
+func (client *WSClient) SetMetrics(metrics Metrics) {
+	client.metrics.set(metrics)
 }
 
-func (client *WSClient) On(event interface{}, listener interface{}) *emission.Emitter {
-	return client.emitter.On(event, listener)
+func (client *WSClient) stats() Metrics {
+	return client.metrics.get()
 }
 
-func (client *WSClient) Emit(event interface{}, arguments ...interface{}) *emission.Emitter {
-	return client.emitter.Emit(event, arguments...)
+func (client *WSClient) log() Logger {
+	return client.logger.get()
 }
 
-func (client *WSClient) Off(event interface{}, listener interface{}) *emission.Emitter {
-	return client.emitter.Off(event, listener)
+func (client *WSClient) OnListenerError(fn func(topic interface{}, err error)) {
+	client.bus.setErrorHandler(fn)
 }
@@ -0,0 +1,365 @@
+package okx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"cadenza-market-connector-okx/pkg/go-okx-api/common"
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/ws"
+
+	"github.com/gorilla/websocket"
+)
+
+// fakeOKX is a websocket server that acknowledges logins, subscribes and
+// unsubscribes like OKX, rejecting the channels in reject.
+type fakeOKX struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	reject   map[string]bool
+	login    string
+	requests []fakeRequest
+	// writeMu serialises the writes to conns.
+	writeMu sync.Mutex
+	conns   []*websocket.Conn
+}
+
+type fakeRequest struct {
+	Id   string
+	Op   string
+	Args []ws.Args
+}
+
+func newFakeOKX(t *testing.T) *fakeOKX {
+	f := &fakeOKX{reject: make(map[string]bool)}
+	upgrader := websocket.Upgrader{}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		f.writeMu.Lock()
+		f.conns = append(f.conns, conn)
+		f.writeMu.Unlock()
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			for _, reply := range f.handle(message) {
+				if err := f.write(conn, reply); err != nil {
+					return
+				}
+			}
+		}
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeOKX) write(conn *websocket.Conn, message []byte) error {
+	f.writeMu.Lock()
+	defer f.writeMu.Unlock()
+	return conn.WriteMessage(websocket.TextMessage, message)
+}
+
+// Push sends message to every connection, like a channel update.
+func (f *fakeOKX) Push(message string) {
+	f.writeMu.Lock()
+	conns := append([]*websocket.Conn(nil), f.conns...)
+	f.writeMu.Unlock()
+	for _, conn := range conns {
+		f.write(conn, []byte(message))
+	}
+}
+
+func (f *fakeOKX) URL() string {
+	return "ws" + strings.TrimPrefix(f.Server.URL, "http")
+}
+
+func (f *fakeOKX) Reject(channel string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.reject[channel] = true
+}
+
+// RejectLogin answers logins with the error code instead of accepting them.
+func (f *fakeOKX) RejectLogin(code string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.login = code
+}
+
+// Requests returns the requests received so far with the given op.
+func (f *fakeOKX) Requests(op string) []fakeRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var requests []fakeRequest
+	for _, request := range f.requests {
+		if request.Op == op {
+			requests = append(requests, request)
+		}
+	}
+	return requests
+}
+
+func (f *fakeOKX) handle(message []byte) [][]byte {
+	if string(message) == "ping" {
+		return [][]byte{[]byte("pong")}
+	}
+	var request struct {
+		Id   string          `json:"id"`
+		Op   string          `json:"op"`
+		Args json.RawMessage `json:"args"`
+	}
+	if err := json.Unmarshal(message, &request); err != nil {
+		return nil
+	}
+	var args []ws.Args
+	json.Unmarshal(request.Args, &args)
+
+	f.mu.Lock()
+	f.requests = append(f.requests, fakeRequest{Id: request.Id, Op: request.Op, Args: args})
+	rejected := false
+	for _, arg := range args {
+		rejected = rejected || f.reject[arg.Channel]
+	}
+	login := f.login
+	f.mu.Unlock()
+
+	var replies [][]byte
+	switch request.Op {
+	case "login":
+		if login != "" {
+			reply, _ := json.Marshal(map[string]string{"event": "error", "code": login, "msg": "login failed"})
+			return append(replies, reply)
+		}
+		replies = append(replies, []byte(`{"event":"login","code":"0","msg":""}`))
+	case "subscribe", "unsubscribe":
+		if rejected {
+			reply, _ := json.Marshal(map[string]string{"id": request.Id, "event": "error", "code": "60018", "msg": "channel doesn't exist"})
+			return append(replies, reply)
+		}
+		for _, arg := range args {
+			reply, _ := json.Marshal(map[string]interface{}{"id": request.Id, "event": request.Op, "arg": arg})
+			replies = append(replies, reply)
+		}
+	}
+	return replies
+}
+
+// newTestClient connects a client to f.
+func newTestClient(t *testing.T, f *fakeOKX) *WSClient {
+	client := newWSClient(EndpointPublic, f.URL(), common.Auth{}, newEventBus(), nil, nil, NopLogger())
+	if !client.IsConnected() {
+		t.Fatal("test client did not connect")
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// eventually fails the test unless cond holds within a second.
+func eventually(t *testing.T, cond func() bool, format string, args ...interface{}) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf(format, args...)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestReconnectReplaysSubscriptions(t *testing.T) {
+	f := newFakeOKX(t)
+	client := newTestClient(t, f)
+
+	btc := ws.Args{Channel: "tickers", InstId: "BTC-USDT"}
+	eth := ws.Args{Channel: "tickers", InstId: "ETH-USDT"}
+	if err := client.Subscribe([]ws.Args{btc, eth}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Unsubscribe([]ws.Args{eth}); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, func() bool { return len(f.Requests("unsubscribe")) == 1 }, "unsubscribe not received")
+
+	client.SetReconnectPolicy(&ReconnectPolicy{InitialDelay: time.Millisecond})
+	client.mu.Lock()
+	conn := client.conn
+	client.mu.Unlock()
+	client.connectionLost(conn, errors.New("test"))
+	eventually(t, func() bool { return len(f.Requests("subscribe")) == 2 }, "subscriptions not replayed")
+	replayed := f.Requests("subscribe")[1].Args
+	if len(replayed) != 1 || replayed[0] != btc {
+		t.Fatalf("replayed %v, want only %v", replayed, btc)
+	}
+}
+
+func TestLoginAwaitsReply(t *testing.T) {
+	f := newFakeOKX(t)
+	client := newTestClient(t, f)
+	if err := client.Login(); err != nil {
+		t.Fatal(err)
+	}
+	if client.State() != StateAuthenticated {
+		t.Fatalf("client is %s after login", client.State())
+	}
+	if err := client.Login(); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(f.Requests("login")); n != 1 {
+		t.Fatalf("%d logins sent, want 1", n)
+	}
+
+	f.RejectLogin("60024")
+	rejected := newTestClient(t, f)
+	err := rejected.Login()
+	var loginErr ws.LoginError
+	if !errors.As(err, &loginErr) || !errors.Is(err, ws.ErrLoginInvalidPassphrase) {
+		t.Fatalf("got %v, want an invalid passphrase", err)
+	}
+	if rejected.State() != StateConnected {
+		t.Fatalf("client is %s after a rejected login", rejected.State())
+	}
+}
+
+func TestEventsRouteOnFullArgs(t *testing.T) {
+	f := newFakeOKX(t)
+	client := newTestClient(t, f)
+
+	var mu sync.Mutex
+	counts := make(map[string]int)
+	count := func(name string) func(interface{}) {
+		return func(interface{}) {
+			mu.Lock()
+			defer mu.Unlock()
+			counts[name]++
+		}
+	}
+	for name, topic := range map[string]interface{}{
+		"swap orders": ws.Args{Channel: "orders", InstType: "SWAP"},
+		"btc ticker":  ws.Args{Channel: "tickers", InstId: "BTC-USDT"},
+		"tickers":     Match{Channel: "tickers"},
+		"swap":        Match{InstType: "SWAP"},
+	} {
+		if _, err := client.On(topic, count(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f.Push(`{"arg":{"channel":"orders","instType":"SWAP"},"data":[{"instType":"SWAP","instId":"BTC-USDT-SWAP"}]}`)
+	f.Push(`{"arg":{"channel":"orders","instType":"SPOT"},"data":[{"instType":"SPOT","instId":"BTC-USDT"}]}`)
+	f.Push(`{"arg":{"channel":"tickers","instId":"BTC-USDT"},"data":[{"instType":"SPOT","instId":"BTC-USDT"}]}`)
+	f.Push(`{"arg":{"channel":"tickers","instId":"ETH-USDT-SWAP"},"data":[{"instType":"SWAP","instId":"ETH-USDT-SWAP"}]}`)
+
+	want := map[string]int{"swap orders": 1, "btc ticker": 1, "tickers": 2, "swap": 2}
+	eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return fmt.Sprint(counts) == fmt.Sprint(want)
+	}, "deliveries %v, want %v", counts, want)
+}
+
+func TestRejectedSubscriptionIsUntracked(t *testing.T) {
+	f := newFakeOKX(t)
+	f.Reject("nope")
+	client := newTestClient(t, f)
+
+	good := ws.Args{Channel: "tickers", InstId: "BTC-USDT"}
+	bad := ws.Args{Channel: "nope", InstId: "BTC-USDT"}
+	if err := client.Subscribe([]ws.Args{good}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Subscribe([]ws.Args{bad}); err != nil {
+		t.Fatal(err)
+	}
+
+	eventually(t, func() bool { return !client.isSubscribed(bad) }, "rejected %v still tracked", bad)
+	if !client.isSubscribed(good) {
+		t.Errorf("acknowledged %v no longer tracked", good)
+	}
+	eventually(t, func() bool {
+		client.ackMu.Lock()
+		defer client.ackMu.Unlock()
+		return len(client.unacked) == 0
+	}, "acknowledged subscribes still awaited")
+}
+
+func TestLoginOutlivesCancelledWaiter(t *testing.T) {
+	attempt := &loginAttempt{done: make(chan struct{}), timeout: time.NewTimer(time.Hour)}
+	client := &WSClient{state: StateLoggingIn, login: attempt}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := client.awaitLogin(ctx, attempt); !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled waiter got %v", err)
+	}
+	if client.State() != StateLoggingIn {
+		t.Fatalf("cancelled waiter moved the client to %s", client.State())
+	}
+
+	waited := make(chan error, 1)
+	go func() { waited <- client.awaitLogin(context.Background(), attempt) }()
+
+	stale := &loginAttempt{done: make(chan struct{})}
+	client.loginResult(stale, ErrLoginTimeout)
+	if client.State() != StateLoggingIn {
+		t.Fatalf("settling a stale attempt moved the client to %s", client.State())
+	}
+
+	client.loginResult(client.pendingLogin(), nil)
+	if err := <-waited; err != nil {
+		t.Fatalf("late login reply not delivered: %v", err)
+	}
+	if client.State() != StateAuthenticated {
+		t.Fatalf("client is %s after login", client.State())
+	}
+}
+
+func TestOKXWsClientRoutesPlainTopicsToEveryClient(t *testing.T) {
+	f := newFakeOKX(t)
+	c := &OKXWsClient{
+		Public:   newTestClient(t, f),
+		Private:  newTestClient(t, f),
+		Business: newTestClient(t, f),
+	}
+
+	var mu sync.Mutex
+	var got []string
+	listener, err := c.On(EventStale, func(stream *StaleStream) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, stream.Args.Channel)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Public.Emit(EventStale, &StaleStream{Args: ws.Args{Channel: "tickers"}})
+	c.Private.Emit(EventStale, &StaleStream{Args: ws.Args{Channel: "orders"}})
+	c.Business.Emit(EventStale, &StaleStream{Args: ws.Args{Channel: "candle1m"}})
+	c.Emit(EventStale, &StaleStream{Args: ws.Args{Channel: "books5"}})
+
+	eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(got) == 4
+	}, "stale events of every client not delivered: %v", got)
+
+	c.Off(listener)
+	c.Business.Emit(EventStale, &StaleStream{Args: ws.Args{Channel: "candle5m"}})
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != 4 {
+		t.Fatalf("removed listener still called: %v", got)
+	}
+}
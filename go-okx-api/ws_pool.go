@@ -0,0 +1,509 @@
+package okx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/ws"
+)
+
+var ErrPoolFull = errors.New("connection pool full")
+
+// PoolConfig limits what one connection of a ConnectionPool carries. The zero
+// value keeps every subscription on a single connection.
+type PoolConfig struct {
+	// MaxSubscriptionsPerConn is the most args subscribed on one connection,
+	// unlimited if zero.
+	MaxSubscriptionsPerConn int
+	// MaxConnections caps the connections of one endpoint, unlimited if zero.
+	MaxConnections int
+}
+
+// ConnectionPool shards the subscriptions of one endpoint over several
+// connections. Every shard publishes on the bus of the primary connection,
+// so listeners registered on it receive events from all of them.
+type ConnectionPool struct {
+	primary *WSClient
+
+	// mu guards the shards and their reservations. A subscribe or
+	// unsubscribe holds it while placing args, not while waiting on OKX.
+	mu       sync.Mutex
+	config   PoolConfig
+	policy   *ReconnectPolicy
+	throttle *ThrottleConfig
+	shards   []*WSClient
+	unwatch  map[*WSClient]func()
+	// pending counts the subscribes and unsubscribes in flight per arg of
+	// each shard. A reserved arg counts as carried by its shard, so
+	// concurrent calls do not place it twice and the shard stays open.
+	pending map[*WSClient]map[ws.Args]int
+}
+
+func newConnectionPool(primary *WSClient) *ConnectionPool {
+	p := &ConnectionPool{
+		primary:  primary,
+		policy:   DefaultReconnectPolicy(),
+		throttle: DefaultThrottleConfig(),
+		shards:   []*WSClient{primary},
+		unwatch:  make(map[*WSClient]func()),
+		pending:  make(map[*WSClient]map[ws.Args]int),
+	}
+	p.watch(primary)
+	return p
+}
+
+// Pool returns the connection pool of an endpoint, EndpointPublic,
+// EndpointPrivate or EndpointBusiness.
+func (c *OKXWsClient) Pool(endpoint string) *ConnectionPool {
+	return c.pools[endpoint]
+}
+
+// SetPoolConfig applies the limits to the pool of every endpoint.
+func (c *OKXWsClient) SetPoolConfig(config PoolConfig) {
+	for _, pool := range c.pools {
+		pool.SetConfig(config)
+	}
+}
+
+// Shards returns the connections of the pool, the primary first.
+func (p *ConnectionPool) Shards() []*WSClient {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]*WSClient{}, p.shards...)
+}
+
+// SetThrottle paces the requests of every connection of every endpoint. A
+// nil config writes them immediately.
+func (c *OKXWsClient) SetThrottle(config *ThrottleConfig) {
+	for _, pool := range c.pools {
+		pool.SetThrottle(config)
+	}
+}
+
+// SetConfig changes the limits and rebalances the subscriptions to meet them.
+func (p *ConnectionPool) SetConfig(config PoolConfig) {
+	p.mu.Lock()
+	p.config = config
+	p.mu.Unlock()
+	p.Rebalance()
+}
+
+func (p *ConnectionPool) SetReconnectPolicy(policy *ReconnectPolicy) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.policy = policy
+	for _, shard := range p.shards {
+		shard.SetReconnectPolicy(policy)
+	}
+}
+
+// SetLogger applies the logger to every connection of every endpoint.
+func (c *OKXWsClient) SetLogger(logger Logger) {
+	for _, pool := range c.pools {
+		for _, shard := range pool.Shards() {
+			shard.SetLogger(logger)
+		}
+	}
+}
+
+// SetMetrics records the activity of every connection of every endpoint.
+func (c *OKXWsClient) SetMetrics(metrics Metrics) {
+	for _, pool := range c.pools {
+		for _, shard := range pool.Shards() {
+			shard.SetMetrics(metrics)
+		}
+	}
+}
+
+// SetVerbose logs every message received on every connection at debug level.
+func (c *OKXWsClient) SetVerbose(verbose bool) {
+	for _, pool := range c.pools {
+		for _, shard := range pool.Shards() {
+			shard.SetVerbose(verbose)
+		}
+	}
+}
+
+// SetThrottle applies the throttle config to every connection of the pool.
+func (p *ConnectionPool) SetThrottle(config *ThrottleConfig) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.throttle = config
+	for _, shard := range p.shards {
+		shard.SetThrottle(config)
+	}
+}
+
+// Rebalance moves the subscriptions of connections that gave up reconnecting
+// and of connections above the limit onto others, opening connections as
+// needed, and closes extra connections left without subscriptions. It runs
+// after every reconnect of a shard.
+func (p *ConnectionPool) Rebalance() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	moved := make(map[ws.Args]bool)
+	for _, shard := range p.shards {
+		state := shard.State()
+		subs := shard.trackedSubscriptions()
+		excess := len(subs) - p.config.MaxSubscriptionsPerConn
+		if state != StateIdle && (p.config.MaxSubscriptionsPerConn == 0 || excess <= 0) {
+			continue
+		}
+
+		var args []ws.Args
+		for arg, auth := range subs {
+			if state != StateIdle && len(args) >= excess {
+				break
+			}
+			args = append(args, arg)
+			moved[arg] = auth
+		}
+		if state != StateIdle {
+			if err := shard.Unsubscribe(args); err != nil {
+				p.primary.log().Warn("rebalance failed to unsubscribe", "endpoint", shard.endpointType, "error", err)
+			}
+		}
+		shard.untrack(args)
+	}
+
+	for auth, args := range splitByAuth(moved) {
+		placed, err := p.assign(args)
+		if err != nil {
+			p.primary.log().Warn("rebalance could not place every arg", "endpoint", p.primary.endpointType, "error", err)
+		}
+		for _, batch := range placed {
+			if err := batch.shard.subscribe(batch.args, auth); err != nil {
+				p.primary.log().Warn("rebalance failed to resubscribe", "endpoint", batch.shard.endpointType, "error", err)
+			}
+		}
+	}
+
+	p.closeEmpty()
+}
+
+func (p *ConnectionPool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var firstErr error
+	for _, shard := range p.shards {
+		p.stopWatching(shard)
+		if err := shard.Close(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("%s close failed: %v", shard.endpointType, err)
+		}
+	}
+	p.shards = []*WSClient{p.primary}
+	return firstErr
+}
+
+// subscribe places args on shards and subscribes them without waiting.
+func (p *ConnectionPool) subscribe(args []ws.Args, auth bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	placed, err := p.assign(args)
+	for _, batch := range placed {
+		if subErr := batch.shard.subscribe(batch.args, auth); subErr != nil && err == nil {
+			err = subErr
+		}
+	}
+	return err
+}
+
+// sendAndWait subscribes or unsubscribes args on the shards carrying them
+// and waits for every acknowledgement. A failed subscribe unsubscribes the
+// args it added on other shards, so it leaves the pool as it was.
+func (p *ConnectionPool) sendAndWait(ctx context.Context, op string, args []ws.Args, auth bool) error {
+	p.mu.Lock()
+	fresh := make(map[ws.Args]bool)
+	var placed []shardArgs
+	var err error
+	if op == "subscribe" {
+		for _, arg := range args {
+			fresh[arg] = p.carrier(arg) == nil
+		}
+		placed, err = p.assign(args)
+	} else {
+		placed = p.locate(args)
+	}
+	if err != nil {
+		// nothing is subscribed, so shards opened for the args are left empty
+		p.closeEmpty()
+		p.mu.Unlock()
+		return err
+	}
+	p.reserve(placed, 1)
+	p.mu.Unlock()
+
+	sent, err := p.sendBatches(ctx, op, placed, auth)
+	if err != nil && op == "subscribe" {
+		p.rollback(sent, fresh)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.reserve(placed, -1)
+	p.closeEmpty()
+	return err
+}
+
+// sendBatches sends op for each batch on its shard, logging in first where
+// needed, and tracks what OKX applied. It stops at the first failure and
+// returns the args applied until then, including those of a batch sent only
+// in part.
+func (p *ConnectionPool) sendBatches(ctx context.Context, op string, placed []shardArgs, auth bool) ([]shardArgs, error) {
+	var sent []shardArgs
+	for _, batch := range placed {
+		shard := batch.shard
+		if auth || shard.endpointType == EndpointPrivate {
+			if err := shard.LoginContext(ctx); err != nil {
+				return sent, err
+			}
+		}
+		n, err := shard.sendAndWait(ctx, op, batch.args)
+		if op == "subscribe" {
+			shard.track(batch.args[:n], auth)
+		} else {
+			shard.untrack(batch.args[:n])
+		}
+		if n > 0 {
+			sent = append(sent, shardArgs{shard: shard, args: batch.args[:n]})
+		}
+		if err != nil {
+			return sent, err
+		}
+	}
+	return sent, nil
+}
+
+// rollback unsubscribes the args of sent that no shard carried before the
+// failed subscribe and no other call is placing meanwhile.
+func (p *ConnectionPool) rollback(sent []shardArgs, fresh map[ws.Args]bool) {
+	p.mu.Lock()
+	var undo []shardArgs
+	for _, batch := range sent {
+		var args []ws.Args
+		for _, arg := range batch.args {
+			if fresh[arg] && p.pending[batch.shard][arg] == 1 {
+				args = append(args, arg)
+			}
+		}
+		if len(args) > 0 {
+			undo = append(undo, shardArgs{shard: batch.shard, args: args})
+		}
+	}
+	p.mu.Unlock()
+
+	// the caller's ctx may be what failed the subscribe
+	ctx, cancel := context.WithTimeout(context.Background(), AckTimeout)
+	defer cancel()
+	for _, batch := range undo {
+		n, err := batch.shard.sendAndWait(ctx, "unsubscribe", batch.args)
+		batch.shard.untrack(batch.args[:n])
+		if err != nil {
+			p.primary.log().Warn("rolling back a failed subscribe failed", "endpoint", batch.shard.endpointType, "args", batch.args, "error", err)
+		}
+	}
+}
+
+// reserve adds delta to the pending count of every placed arg. The caller
+// must hold mu.
+func (p *ConnectionPool) reserve(placed []shardArgs, delta int) {
+	for _, batch := range placed {
+		counts := p.pending[batch.shard]
+		if counts == nil {
+			counts = make(map[ws.Args]int)
+			p.pending[batch.shard] = counts
+		}
+		for _, arg := range batch.args {
+			if counts[arg] += delta; counts[arg] <= 0 {
+				delete(counts, arg)
+			}
+		}
+		if len(counts) == 0 {
+			delete(p.pending, batch.shard)
+		}
+	}
+}
+
+// load returns the number of args shard carries or is reserved for. The
+// caller must hold mu.
+func (p *ConnectionPool) load(shard *WSClient) int {
+	n := shard.subscriptionCount()
+	for arg := range p.pending[shard] {
+		if !shard.isSubscribed(arg) {
+			n++
+		}
+	}
+	return n
+}
+
+type shardArgs struct {
+	shard *WSClient
+	args  []ws.Args
+}
+
+// assign chooses a shard for each arg: the one already carrying it or
+// reserved for it, else the least loaded shard with room, else a new one. Args that fit nowhere make
+// it return an error, ErrPoolFull at MaxConnections, along with the args it
+// did place. The caller must hold mu.
+func (p *ConnectionPool) assign(args []ws.Args) ([]shardArgs, error) {
+	load := make(map[*WSClient]int, len(p.shards))
+	for _, shard := range p.shards {
+		load[shard] = p.load(shard)
+	}
+
+	var placed []shardArgs
+	index := make(map[*WSClient]int)
+	var unplaced int
+	var openErr error
+	for _, arg := range args {
+		shard := p.carrier(arg)
+		if shard == nil {
+			shard = p.leastLoaded(load)
+			if shard == nil && openErr == nil {
+				if shard, openErr = p.open(); shard != nil {
+					load[shard] = 0
+				}
+			}
+			if shard == nil {
+				unplaced++
+				continue
+			}
+			// an arg the shard already carries adds nothing to its load
+			load[shard]++
+		}
+
+		i, ok := index[shard]
+		if !ok {
+			i = len(placed)
+			index[shard] = i
+			placed = append(placed, shardArgs{shard: shard})
+		}
+		placed[i].args = append(placed[i].args, arg)
+	}
+
+	if unplaced > 0 {
+		return placed, fmt.Errorf("%d of %d args not placed on %s: %w", unplaced, len(args), p.primary.endpointType, openErr)
+	}
+	return placed, nil
+}
+
+// locate groups args by the shard carrying them, the primary for args no
+// shard carries. The caller must hold mu.
+func (p *ConnectionPool) locate(args []ws.Args) []shardArgs {
+	var placed []shardArgs
+	index := make(map[*WSClient]int)
+	for _, arg := range args {
+		shard := p.carrier(arg)
+		if shard == nil {
+			shard = p.primary
+		}
+		i, ok := index[shard]
+		if !ok {
+			i = len(placed)
+			index[shard] = i
+			placed = append(placed, shardArgs{shard: shard})
+		}
+		placed[i].args = append(placed[i].args, arg)
+	}
+	return placed
+}
+
+// carrier returns the shard carrying arg or reserved for it, nil if there is
+// none. The caller must hold mu.
+func (p *ConnectionPool) carrier(arg ws.Args) *WSClient {
+	for _, shard := range p.shards {
+		if shard.isSubscribed(arg) || p.pending[shard][arg] > 0 {
+			return shard
+		}
+	}
+	return nil
+}
+
+// leastLoaded returns the shard with the fewest subscriptions that is not
+// down and still has room, nil if there is none.
+func (p *ConnectionPool) leastLoaded(load map[*WSClient]int) *WSClient {
+	var best *WSClient
+	for _, shard := range p.shards {
+		if state := shard.State(); state == StateIdle || state == StateClosed {
+			continue
+		}
+		if p.config.MaxSubscriptionsPerConn > 0 && load[shard] >= p.config.MaxSubscriptionsPerConn {
+			continue
+		}
+		if best == nil || load[shard] < load[best] {
+			best = shard
+		}
+	}
+	return best
+}
+
+// open adds a connection to the pool unless it is at MaxConnections.
+func (p *ConnectionPool) open() (*WSClient, error) {
+	if p.config.MaxConnections > 0 && len(p.shards) >= p.config.MaxConnections {
+		return nil, ErrPoolFull
+	}
+	shard := newWSClient(p.primary.endpointType, p.primary.endpoint, p.primary.auth, p.primary.bus, p.policy, p.throttle, p.primary.log())
+	shard.SetVerbose(p.primary.verbose.Load())
+	shard.SetMetrics(p.primary.stats())
+	shard.SetWatchdog(p.primary.watchdog.Load())
+	if !shard.IsConnected() {
+		shard.Close()
+		return nil, fmt.Errorf("opening %s connection failed", p.primary.endpointType)
+	}
+	p.shards = append(p.shards, shard)
+	p.watch(shard)
+	return shard, nil
+}
+
+// closeEmpty closes the shards other than the primary that carry nothing
+// and are not reserved for a call in flight. The caller must hold mu.
+func (p *ConnectionPool) closeEmpty() {
+	shards := p.shards[:1]
+	for _, shard := range p.shards[1:] {
+		if p.load(shard) > 0 {
+			shards = append(shards, shard)
+			continue
+		}
+		p.stopWatching(shard)
+		if err := shard.Close(); err != nil {
+			p.primary.log().Warn("closing empty shard failed", "endpoint", shard.endpointType, "error", err)
+		}
+	}
+	p.shards = shards
+}
+
+// watch rebalances the pool whenever shard reconnected or gave up trying.
+func (p *ConnectionPool) watch(shard *WSClient) {
+	shard.OnReconnected(func() {
+		go p.Rebalance()
+	})
+	changes, cancel := shard.StateChanges(8)
+	p.unwatch[shard] = cancel
+	go func() {
+		for change := range changes {
+			if change.From == StateReconnecting && change.To == StateIdle {
+				go p.Rebalance()
+			}
+		}
+	}()
+}
+
+// stopWatching ends the state stream watch opened for shard, if any. The
+// caller must hold mu.
+func (p *ConnectionPool) stopWatching(shard *WSClient) {
+	if cancel, ok := p.unwatch[shard]; ok {
+		cancel()
+		delete(p.unwatch, shard)
+	}
+}
+
+func splitByAuth(args map[ws.Args]bool) map[bool][]ws.Args {
+	split := make(map[bool][]ws.Args)
+	for arg, auth := range args {
+		split[auth] = append(split[auth], arg)
+	}
+	return split
+}
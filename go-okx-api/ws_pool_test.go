@@ -0,0 +1,265 @@
+package okx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/ws"
+)
+
+// newTestPool returns a pool over f whose connections neither reconnect nor
+// throttle.
+func newTestPool(t *testing.T, f *fakeOKX, config PoolConfig) *ConnectionPool {
+	p := newConnectionPool(newTestClient(t, f))
+	p.policy = nil
+	p.throttle = nil
+	p.config = config
+	t.Cleanup(func() { p.Close() })
+	return p
+}
+
+func tickerArgs(instIds ...string) []ws.Args {
+	args := make([]ws.Args, len(instIds))
+	for i, instId := range instIds {
+		args[i] = ws.Args{Channel: "tickers", InstId: instId}
+	}
+	return args
+}
+
+func subscribeAndWait(t *testing.T, p *ConnectionPool, args []ws.Args) error {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return p.sendAndWait(ctx, "subscribe", args, false)
+}
+
+// shardLoads returns the subscription count of every shard, the primary
+// first.
+func shardLoads(p *ConnectionPool) []int {
+	var loads []int
+	for _, shard := range p.Shards() {
+		loads = append(loads, shard.subscriptionCount())
+	}
+	return loads
+}
+
+func TestPoolCloseTwice(t *testing.T) {
+	f := newFakeOKX(t)
+	p := newTestPool(t, f, PoolConfig{MaxSubscriptionsPerConn: 1})
+	if err := subscribeAndWait(t, p, tickerArgs("BTC-USDT", "ETH-USDT")); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(p.Shards()); n != 2 {
+		t.Fatalf("%d shards, want 2", n)
+	}
+
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPoolAssign(t *testing.T) {
+	f := newFakeOKX(t)
+	p := newTestPool(t, f, PoolConfig{MaxSubscriptionsPerConn: 2, MaxConnections: 2})
+
+	if err := subscribeAndWait(t, p, tickerArgs("A", "B", "C")); err != nil {
+		t.Fatal(err)
+	}
+	if loads := fmt.Sprint(shardLoads(p)); loads != "[2 1]" {
+		t.Fatalf("loads %s, want [2 1]", loads)
+	}
+
+	// an arg already carried stays on its shard
+	p.mu.Lock()
+	placed, err := p.assign(tickerArgs("C"))
+	p.mu.Unlock()
+	if err != nil || len(placed) != 1 || !placed[0].shard.isSubscribed(tickerArgs("C")[0]) {
+		t.Fatalf("C placed on %+v, %v, want its carrier", placed, err)
+	}
+
+	// one arg fits, the other exceeds MaxConnections
+	err = subscribeAndWait(t, p, tickerArgs("D", "E"))
+	if !errors.Is(err, ErrPoolFull) {
+		t.Fatalf("got %v, want ErrPoolFull", err)
+	}
+	if loads := fmt.Sprint(shardLoads(p)); loads != "[2 1]" {
+		t.Fatalf("loads %s after a failed placement, want [2 1]", loads)
+	}
+}
+
+func TestPoolAssignFailureClosesNewShards(t *testing.T) {
+	f := newFakeOKX(t)
+	p := newTestPool(t, f, PoolConfig{MaxSubscriptionsPerConn: 1, MaxConnections: 2})
+	if err := subscribeAndWait(t, p, tickerArgs("A")); err != nil {
+		t.Fatal(err)
+	}
+
+	// B opens a second shard, C fits nowhere
+	if err := subscribeAndWait(t, p, tickerArgs("B", "C")); !errors.Is(err, ErrPoolFull) {
+		t.Fatalf("got %v, want ErrPoolFull", err)
+	}
+	if n := len(p.Shards()); n != 1 {
+		t.Fatalf("%d shards left open, want only the primary", n)
+	}
+}
+
+func TestPoolLocate(t *testing.T) {
+	f := newFakeOKX(t)
+	p := newTestPool(t, f, PoolConfig{MaxSubscriptionsPerConn: 1})
+	if err := subscribeAndWait(t, p, tickerArgs("A", "B")); err != nil {
+		t.Fatal(err)
+	}
+	shards := p.Shards()
+
+	p.mu.Lock()
+	located := p.locate(tickerArgs("B", "A", "unknown"))
+	p.mu.Unlock()
+	got := make(map[string]*WSClient)
+	for _, batch := range located {
+		for _, arg := range batch.args {
+			got[arg.InstId] = batch.shard
+		}
+	}
+	if got["A"] != shards[0] || got["B"] != shards[1] {
+		t.Fatalf("A and B not located on their carriers")
+	}
+	if got["unknown"] != shards[0] {
+		t.Fatalf("an arg no shard carries is not located on the primary")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := p.sendAndWait(ctx, "unsubscribe", tickerArgs("B"), false); err != nil {
+		t.Fatal(err)
+	}
+	// the shard B leaves empty is closed
+	if loads := fmt.Sprint(shardLoads(p)); loads != "[1]" {
+		t.Fatalf("loads %s after unsubscribing B, want [1]", loads)
+	}
+}
+
+func TestPoolRebalanceOverfull(t *testing.T) {
+	f := newFakeOKX(t)
+	p := newTestPool(t, f, PoolConfig{})
+	if err := subscribeAndWait(t, p, tickerArgs("A", "B", "C", "D")); err != nil {
+		t.Fatal(err)
+	}
+
+	p.SetConfig(PoolConfig{MaxSubscriptionsPerConn: 2})
+	if loads := fmt.Sprint(shardLoads(p)); loads != "[2 2]" {
+		t.Fatalf("loads %s after lowering the limit, want [2 2]", loads)
+	}
+	eventually(t, func() bool { return len(f.Requests("unsubscribe")) == 1 }, "excess args not unsubscribed from the primary")
+}
+
+func TestPoolRebalanceDeadShard(t *testing.T) {
+	f := newFakeOKX(t)
+	p := newTestPool(t, f, PoolConfig{MaxSubscriptionsPerConn: 2})
+	if err := subscribeAndWait(t, p, tickerArgs("A", "B", "C")); err != nil {
+		t.Fatal(err)
+	}
+	shards := p.Shards()
+	if len(shards) != 2 {
+		t.Fatalf("%d shards, want 2", len(shards))
+	}
+
+	// the primary drops without reconnecting, as if it gave up
+	primary := shards[0]
+	primary.mu.Lock()
+	conn := primary.conn
+	primary.mu.Unlock()
+	primary.connectionLost(conn, errors.New("test"))
+	if primary.State() != StateIdle {
+		t.Fatalf("primary is %s, want idle", primary.State())
+	}
+
+	p.Rebalance()
+	if n := primary.subscriptionCount(); n != 0 {
+		t.Fatalf("dead primary still carries %d args", n)
+	}
+	moved := 0
+	for _, shard := range p.Shards()[1:] {
+		moved += shard.subscriptionCount()
+	}
+	if moved != 3 {
+		t.Fatalf("%d args carried by live shards, want 3", moved)
+	}
+}
+
+func TestPoolAssignCountsCarriedArgsOnce(t *testing.T) {
+	f := newFakeOKX(t)
+	p := newTestPool(t, f, PoolConfig{MaxSubscriptionsPerConn: 3})
+	if err := subscribeAndWait(t, p, tickerArgs("A", "B")); err != nil {
+		t.Fatal(err)
+	}
+	if err := subscribeAndWait(t, p, tickerArgs("A", "C")); err != nil {
+		t.Fatal(err)
+	}
+	if loads := fmt.Sprint(shardLoads(p)); loads != "[3]" {
+		t.Fatalf("loads %s, want C on the primary beside the carried A", loads)
+	}
+}
+
+func TestPoolRejectedSubscribeRollsBack(t *testing.T) {
+	f := newFakeOKX(t)
+	f.Reject("nope")
+	p := newTestPool(t, f, PoolConfig{MaxSubscriptionsPerConn: 1})
+	if err := subscribeAndWait(t, p, tickerArgs("A")); err != nil {
+		t.Fatal(err)
+	}
+
+	// B opens a second shard and is acknowledged, nope opens a third and is
+	// rejected
+	args := append(tickerArgs("A", "B"), ws.Args{Channel: "nope", InstId: "C"})
+	var okxErr ws.OKXError
+	if err := subscribeAndWait(t, p, args); !errors.As(err, &okxErr) {
+		t.Fatalf("got %v, want the rejection", err)
+	}
+	if loads := fmt.Sprint(shardLoads(p)); loads != "[1]" {
+		t.Fatalf("loads %s after a rejected subscribe, want only A on the primary", loads)
+	}
+	unsubscribes := f.Requests("unsubscribe")
+	if len(unsubscribes) != 1 || fmt.Sprint(unsubscribes[0].Args) != fmt.Sprint(tickerArgs("B")) {
+		t.Fatalf("unsubscribed %+v, want only B", unsubscribes)
+	}
+}
+
+func TestPoolWaitsWithoutLock(t *testing.T) {
+	f := newFakeOKX(t)
+	p := newTestPool(t, f, PoolConfig{})
+	if err := subscribeAndWait(t, p, tickerArgs("A")); err != nil {
+		t.Fatal(err)
+	}
+	// the hourly window is used up, so the next subscribe waits on the
+	// throttle until its context ends
+	p.primary.SetThrottle(&ThrottleConfig{OpsPerHour: 1})
+	p.primary.throttle.acquire(context.Background(), "subscribe")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- p.sendAndWait(ctx, "subscribe", tickerArgs("B"), false) }()
+	eventually(t, func() bool {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return len(p.pending) > 0
+	}, "subscribe not in flight")
+
+	shards := make(chan []*WSClient, 1)
+	go func() { shards <- p.Shards() }()
+	select {
+	case <-shards:
+	case <-time.After(time.Second):
+		t.Fatal("pool locked while a subscribe waits")
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want the context's error", err)
+	}
+}
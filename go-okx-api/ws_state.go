@@ -0,0 +1,195 @@
+package okx
+
+import (
+	"sync"
+	"time"
+)
+
+// ConnectionState is a step in the lifecycle of a WSClient connection.
+type ConnectionState int
+
+const (
+	StateIdle ConnectionState = iota
+	StateConnecting
+	StateConnected
+	StateLoggingIn
+	StateAuthenticated
+	StateReconnecting
+	StateClosed
+)
+
+func (s ConnectionState) String() string {
+	switch s {
+	case StateIdle:
+		return "idle"
+	case StateConnecting:
+		return "connecting"
+	case StateConnected:
+		return "connected"
+	case StateLoggingIn:
+		return "logging_in"
+	case StateAuthenticated:
+		return "authenticated"
+	case StateReconnecting:
+		return "reconnecting"
+	case StateClosed:
+		return "closed"
+	}
+	return "unknown"
+}
+
+// IsOpen reports whether the websocket is dialed, whether or not it is
+// logged in.
+func (s ConnectionState) IsOpen() bool {
+	return s == StateConnected || s == StateLoggingIn || s == StateAuthenticated
+}
+
+// StateChange describes a transition of a WSClient. Err is the cause of the
+// transition when it was triggered by a failure.
+type StateChange struct {
+	EndpointType string
+	From         ConnectionState
+	To           ConnectionState
+	Err          error
+	Time         time.Time
+}
+
+type stateHooks struct {
+	mu             sync.Mutex
+	streams        []chan StateChange
+	onConnected    []func()
+	onDisconnected []func(err error)
+	onReconnected  []func()
+	onLoginFailed  []func(err error)
+}
+
+func (client *WSClient) State() ConnectionState {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	return client.state
+}
+
+func (client *WSClient) IsConnected() bool {
+	return client.State().IsOpen()
+}
+
+// StateChanges returns a stream of every state transition of the client and
+// a function that ends the stream. Transitions are dropped while the buffer
+// is full, so a slow reader never stalls the connection.
+func (client *WSClient) StateChanges(buffer int) (<-chan StateChange, func()) {
+	ch := make(chan StateChange, buffer)
+	client.hooks.mu.Lock()
+	client.hooks.streams = append(client.hooks.streams, ch)
+	client.hooks.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			client.hooks.mu.Lock()
+			defer client.hooks.mu.Unlock()
+			for i, stream := range client.hooks.streams {
+				if stream == ch {
+					client.hooks.streams = append(client.hooks.streams[:i], client.hooks.streams[i+1:]...)
+					break
+				}
+			}
+			close(ch)
+		})
+	}
+}
+
+// OnConnected is called every time a connection is established, including
+// after a reconnect.
+func (client *WSClient) OnConnected(fn func()) {
+	client.hooks.mu.Lock()
+	defer client.hooks.mu.Unlock()
+	client.hooks.onConnected = append(client.hooks.onConnected, fn)
+}
+
+// OnDisconnected is called when an open connection is lost or closed. err is
+// nil when the client was closed deliberately.
+func (client *WSClient) OnDisconnected(fn func(err error)) {
+	client.hooks.mu.Lock()
+	defer client.hooks.mu.Unlock()
+	client.hooks.onDisconnected = append(client.hooks.onDisconnected, fn)
+}
+
+// OnReconnected is called once a reconnect has re-dialed, logged in where
+// required and replayed every subscription.
+func (client *WSClient) OnReconnected(fn func()) {
+	client.hooks.mu.Lock()
+	defer client.hooks.mu.Unlock()
+	client.hooks.onReconnected = append(client.hooks.onReconnected, fn)
+}
+
+func (client *WSClient) OnLoginFailed(fn func(err error)) {
+	client.hooks.mu.Lock()
+	defer client.hooks.mu.Unlock()
+	client.hooks.onLoginFailed = append(client.hooks.onLoginFailed, fn)
+}
+
+// setState moves the client to a new state. The caller must hold mu and pass
+// the returned change to publish once it has released it.
+func (client *WSClient) setState(to ConnectionState, err error) *StateChange {
+	if client.state == to {
+		return nil
+	}
+	change := &StateChange{
+		EndpointType: client.endpointType,
+		From:         client.state,
+		To:           to,
+		Err:          err,
+		Time:         time.Now(),
+	}
+	client.state = to
+	return change
+}
+
+// publish notifies the state stream and lifecycle callbacks of a change.
+func (client *WSClient) publish(change *StateChange) {
+	if change == nil {
+		return
+	}
+
+	client.hooks.mu.Lock()
+	for _, stream := range client.hooks.streams {
+		select {
+		case stream <- *change:
+		default:
+		}
+	}
+	var connected []func()
+	var disconnected []func(error)
+	if change.From == StateConnecting && change.To == StateConnected {
+		connected = append(connected, client.hooks.onConnected...)
+	}
+	if change.From.IsOpen() && !change.To.IsOpen() {
+		disconnected = append(disconnected, client.hooks.onDisconnected...)
+	}
+	client.hooks.mu.Unlock()
+
+	for _, fn := range connected {
+		fn()
+	}
+	for _, fn := range disconnected {
+		fn(change.Err)
+	}
+}
+
+func (client *WSClient) fireReconnected() {
+	client.hooks.mu.Lock()
+	hooks := append([]func(){}, client.hooks.onReconnected...)
+	client.hooks.mu.Unlock()
+	for _, fn := range hooks {
+		fn()
+	}
+}
+
+func (client *WSClient) fireLoginFailed(err error) {
+	client.hooks.mu.Lock()
+	hooks := append([]func(error){}, client.hooks.onLoginFailed...)
+	client.hooks.mu.Unlock()
+	for _, fn := range hooks {
+		fn(err)
+	}
+}
@@ -0,0 +1,219 @@
+package okx
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/ws"
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/ws/business"
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/ws/private"
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/ws/public"
+)
+
+// Subscription is the handle of a typed subscription. Its channel is closed
+// once it is unsubscribed or its context is done.
+//
+// The channel is unbuffered. Events wait for the reader in a queue per arg
+// configured by OKXWsClient.SubscriptionQueue, which blocks the connection
+// once full unless its policy drops or conflates events.
+type Subscription struct {
+	client *OKXWsClient
+	args   []ws.Args
+	done   chan struct{}
+	once   sync.Once
+	err    error
+
+	// mu guards closing the event channel against a delivery in progress.
+	mu        sync.RWMutex
+	closed    bool
+	close     func()
+	listeners []*Listener
+}
+
+func (s *Subscription) Args() []ws.Args {
+	return s.args
+}
+
+// Stats sums the delivery counters of the subscription's queues.
+func (s *Subscription) Stats() QueueStats {
+	var stats QueueStats
+	for _, l := range s.listeners {
+		stats = stats.add(l.Stats())
+	}
+	return stats
+}
+
+// Done is closed when the subscription ends.
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Unsubscribe stops delivery, closes the event channel and unsubscribes the
+// args on OKX that no other Subscription or listener registered with On
+// still receives.
+func (s *Subscription) Unsubscribe() error {
+	s.once.Do(func() {
+		s.stop()
+		if unused := s.client.release(s.args); len(unused) > 0 {
+			s.err = s.client.UnsubscribeAndWait(context.Background(), unused)
+		}
+	})
+	return s.err
+}
+
+func (s *Subscription) stop() {
+	for _, l := range s.listeners {
+		l.Remove()
+	}
+	close(s.done)
+	s.mu.Lock()
+	s.closed = true
+	s.close()
+	s.mu.Unlock()
+}
+
+// SubscribeTickers streams the tickers channel of every instId.
+func (c *OKXWsClient) SubscribeTickers(ctx context.Context, instIds []string) (<-chan public.TickerEvent, *Subscription, error) {
+	return Subscribe[public.TickerEvent](ctx, c, instArgs("tickers", instIds))
+}
+
+func (c *OKXWsClient) SubscribeTrades(ctx context.Context, instIds []string) (<-chan public.TradeEvent, *Subscription, error) {
+	return Subscribe[public.TradeEvent](ctx, c, instArgs("trades", instIds))
+}
+
+// SubscribeOrderBooks streams the top 5 levels of the order book (books5).
+func (c *OKXWsClient) SubscribeOrderBooks(ctx context.Context, instIds []string) (<-chan public.OrderBookEvent, *Subscription, error) {
+	return Subscribe[public.OrderBookEvent](ctx, c, instArgs("books5", instIds))
+}
+
+// SubscribeCandles streams 1-minute candlesticks (candle1m).
+func (c *OKXWsClient) SubscribeCandles(ctx context.Context, instIds []string) (<-chan business.CandleEvent, *Subscription, error) {
+	return Subscribe[business.CandleEvent](ctx, c, instArgs("candle1m", instIds))
+}
+
+// SubscribeOrders streams order updates of instType, e.g. SPOT or ANY,
+// limited to instIds when any are given.
+func (c *OKXWsClient) SubscribeOrders(ctx context.Context, instType string, instIds []string) (<-chan private.OrderEvent, *Subscription, error) {
+	args := []ws.Args{{Channel: "orders", InstType: instType}}
+	if len(instIds) > 0 {
+		args = instArgs("orders", instIds)
+		for i := range args {
+			args[i].InstType = instType
+		}
+	}
+	return Subscribe[private.OrderEvent](ctx, c, args)
+}
+
+func instArgs(channel string, instIds []string) []ws.Args {
+	args := make([]ws.Args, 0, len(instIds))
+	for _, instId := range instIds {
+		args = append(args, ws.Args{Channel: channel, InstId: instId})
+	}
+	return args
+}
+
+// Subscribe streams the events of args as T on any channel of the catalog
+// that decodes its pushes into a *T, e.g. Subscribe[public.OrderBookEvent]
+// on books or bbo-tbt. It queues events for the channel, then subscribes
+// and waits for OKX to acknowledge. Payloads that failed to decode into T go
+// to the listener error hook rather than the channel.
+func Subscribe[T any](ctx context.Context, c *OKXWsClient, args []ws.Args) (<-chan T, *Subscription, error) {
+	for _, arg := range args {
+		if err := checkDecodes[T](arg); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	ch := make(chan T)
+	sub := &Subscription{
+		client: c,
+		args:   args,
+		done:   make(chan struct{}),
+		close:  func() { close(ch) },
+	}
+
+	listener := func(event *T) {
+		sub.mu.RLock()
+		defer sub.mu.RUnlock()
+		if sub.closed {
+			return
+		}
+		select {
+		case ch <- *event:
+		case <-sub.done:
+		}
+	}
+	for _, arg := range args {
+		sub.listeners = append(sub.listeners, ListenQueued(c.clientFor(arg.Channel), arg, c.SubscriptionQueue, listener))
+	}
+
+	// hold the args before subscribing, so a Subscription of the same args
+	// ending meanwhile leaves them subscribed
+	c.hold(args)
+	if err := c.SubscribeAndWait(ctx, args); err != nil {
+		sub.once.Do(sub.stop)
+		c.release(args)
+		return nil, nil, err
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			sub.Unsubscribe()
+		case <-sub.done:
+		}
+	}()
+	return ch, sub, nil
+}
+
+// checkDecodes verifies that the catalog knows the channel of arg, that arg
+// sets the keys it requires and that its pushes decode into a *T.
+func checkDecodes[T any](arg ws.Args) error {
+	spec, ok := Channels.Lookup(arg.Channel)
+	if !ok || spec.Decode == nil {
+		return fmt.Errorf("channel %s has no decoder in the catalog", arg.Channel)
+	}
+	if err := spec.Validate(arg); err != nil {
+		return err
+	}
+	event, err := spec.Decode([]byte("{}"))
+	if err != nil {
+		return fmt.Errorf("channel %s: %w", arg.Channel, err)
+	}
+	if _, ok := event.(*T); !ok {
+		return fmt.Errorf("channel %s decodes into %T, not *%T", arg.Channel, event, *new(T))
+	}
+	return nil
+}
+
+// hold counts a Subscription of each arg.
+func (c *OKXWsClient) hold(args []ws.Args) {
+	c.holdMu.Lock()
+	defer c.holdMu.Unlock()
+	if c.holders == nil {
+		c.holders = make(map[ws.Args]int)
+	}
+	for _, arg := range args {
+		c.holders[arg]++
+	}
+}
+
+// release drops a Subscription of each arg and returns the args left without
+// one or a listener of their exact args, which are no longer needed on OKX.
+// The caller must have removed its own listeners first.
+func (c *OKXWsClient) release(args []ws.Args) []ws.Args {
+	c.holdMu.Lock()
+	defer c.holdMu.Unlock()
+	var unused []ws.Args
+	for _, arg := range args {
+		if c.holders[arg]--; c.holders[arg] > 0 {
+			continue
+		}
+		delete(c.holders, arg)
+		if !c.clientFor(arg.Channel).bus.has(arg) {
+			unused = append(unused, arg)
+		}
+	}
+	return unused
+}
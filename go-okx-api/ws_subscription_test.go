@@ -0,0 +1,88 @@
+package okx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/ws"
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/ws/public"
+)
+
+// newTestOKXClient serves every endpoint of an OKXWsClient from f.
+func newTestOKXClient(t *testing.T, f *fakeOKX) *OKXWsClient {
+	c := &OKXWsClient{pools: make(map[string]*ConnectionPool)}
+	for _, endpoint := range []string{EndpointPublic, EndpointPrivate, EndpointBusiness} {
+		c.pools[endpoint] = newTestPool(t, f, PoolConfig{})
+	}
+	c.Public = c.pools[EndpointPublic].primary
+	c.Private = c.pools[EndpointPrivate].primary
+	c.Business = c.pools[EndpointBusiness].primary
+	return c
+}
+
+func TestSubscriptionsShareArgs(t *testing.T) {
+	f := newFakeOKX(t)
+	c := newTestOKXClient(t, f)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	first, firstSub, err := c.SubscribeTickers(ctx, []string{"BTC-USDT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, secondSub, err := c.SubscribeTickers(ctx, []string{"BTC-USDT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener, err := c.On(ws.Args{Channel: "tickers", InstId: "BTC-USDT"}, func(*public.TickerEvent) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := secondSub.Unsubscribe(); err != nil {
+		t.Fatal(err)
+	}
+	f.Push(`{"arg":{"channel":"tickers","instId":"BTC-USDT"},"data":[{"instId":"BTC-USDT","last":"1"}]}`)
+	select {
+	case e := <-first:
+		if e.Data[0].Last != "1" {
+			t.Fatalf("got %+v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("remaining subscription stopped receiving")
+	}
+
+	if err := firstSub.Unsubscribe(); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(f.Requests("unsubscribe")); n != 0 {
+		t.Fatalf("%d unsubscribes sent while an On listener remains, want 0", n)
+	}
+
+	_, sub, err := c.SubscribeTickers(ctx, []string{"BTC-USDT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Off(listener)
+	if err := sub.Unsubscribe(); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(f.Requests("unsubscribe")); n != 1 {
+		t.Fatalf("%d unsubscribes sent once the last subscriber left, want 1", n)
+	}
+}
+
+func TestSubscribeChecksCatalog(t *testing.T) {
+	c := &OKXWsClient{}
+	ctx := context.Background()
+	if _, _, err := Subscribe[public.TickerEvent](ctx, c, []ws.Args{{Channel: "books", InstId: "BTC-USDT"}}); err == nil {
+		t.Error("subscribed tickers events on books")
+	}
+	if _, _, err := Subscribe[public.TickerEvent](ctx, c, []ws.Args{{Channel: "tickers"}}); err == nil {
+		t.Error("subscribed tickers without an instId")
+	}
+	if _, _, err := Subscribe[public.TickerEvent](ctx, c, []ws.Args{{Channel: "price-limit", InstId: "BTC-USDT"}}); err == nil {
+		t.Error("subscribed a channel without a decoder")
+	}
+}
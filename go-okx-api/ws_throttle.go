@@ -0,0 +1,221 @@
+package okx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/ws"
+)
+
+// ThrottleConfig paces the requests written to one connection so that OKX's
+// limits are never hit. A zero rate leaves that kind of request unpaced.
+type ThrottleConfig struct {
+	// OpsPerSecond and OpsPerHour limit subscribe, unsubscribe and login
+	// requests.
+	OpsPerSecond int
+	OpsPerHour   int
+	// TradeOpsPerSecond limits order, cancel and amend ops.
+	TradeOpsPerSecond int
+	// MaxMessageSize is the largest request in bytes. Subscribe and
+	// unsubscribe requests above it are split, other requests rejected.
+	MaxMessageSize int
+}
+
+// DefaultThrottleConfig follows OKX's limits of 480 subscribe, unsubscribe
+// and login requests per connection and hour, 60 order, cancel or amend ops
+// per 2 seconds, paced here as 30 a second, and 64 KB per request. OKX
+// counts trading ops per instrument, so a connection trading several may
+// raise TradeOpsPerSecond.
+func DefaultThrottleConfig() *ThrottleConfig {
+	return &ThrottleConfig{
+		OpsPerSecond:      3,
+		OpsPerHour:        480,
+		TradeOpsPerSecond: 30,
+		MaxMessageSize:    64 * 1024,
+	}
+}
+
+// throttle is the outbound queue of a connection. Requests of one kind wait
+// their turn in order; trading ops never queue behind subscriptions.
+type throttle struct {
+	config ThrottleConfig
+
+	controlMu sync.Mutex
+	control   []time.Time
+	tradeMu   sync.Mutex
+	trades    []time.Time
+}
+
+func newThrottle(config *ThrottleConfig) *throttle {
+	if config == nil {
+		return nil
+	}
+	return &throttle{config: *config}
+}
+
+// reset forgets past requests once a new connection is established, as
+// OKX counts them per connection.
+func (t *throttle) reset() {
+	t.controlMu.Lock()
+	t.control = nil
+	t.controlMu.Unlock()
+	t.tradeMu.Lock()
+	t.trades = nil
+	t.tradeMu.Unlock()
+}
+
+// acquire blocks until a request of op may be written and records it.
+func (t *throttle) acquire(ctx context.Context, op string) error {
+	switch op {
+	case "subscribe", "unsubscribe", "login":
+		t.controlMu.Lock()
+		defer t.controlMu.Unlock()
+		sent, err := pace(ctx, t.control, []window{
+			{time.Second, t.config.OpsPerSecond},
+			{time.Hour, t.config.OpsPerHour},
+		})
+		t.control = sent
+		return err
+	default:
+		t.tradeMu.Lock()
+		defer t.tradeMu.Unlock()
+		sent, err := pace(ctx, t.trades, []window{
+			{time.Second, t.config.TradeOpsPerSecond},
+		})
+		t.trades = sent
+		return err
+	}
+}
+
+type window struct {
+	length time.Duration
+	limit  int
+}
+
+// pace waits until every window has room for one more request and returns
+// the send times still inside the longest window, including this one.
+func pace(ctx context.Context, sent []time.Time, windows []window) ([]time.Time, error) {
+	for {
+		now := time.Now()
+		var longest time.Duration
+		var wait time.Duration
+		for _, w := range windows {
+			if w.limit <= 0 {
+				continue
+			}
+			if w.length > longest {
+				longest = w.length
+			}
+			recent := inWindow(sent, now, w.length)
+			if len(recent) >= w.limit {
+				// room once the oldest request that counts leaves the window
+				if d := recent[len(recent)-w.limit].Add(w.length).Sub(now); d > wait {
+					wait = d
+				}
+			}
+		}
+		if wait <= 0 {
+			if longest == 0 {
+				return nil, nil
+			}
+			return append(inWindow(sent, now, longest), now), nil
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return sent, ctx.Err()
+		}
+	}
+}
+
+// inWindow returns the tail of sent, oldest first, within length of now.
+func inWindow(sent []time.Time, now time.Time, length time.Duration) []time.Time {
+	for i, t := range sent {
+		if now.Sub(t) < length {
+			return sent[i:]
+		}
+	}
+	return nil
+}
+
+// encodedMessage is one message of a request and the number of its args.
+type encodedMessage struct {
+	data []byte
+	args int
+}
+
+// encodeRequest marshals request, splitting the args of subscribe and
+// unsubscribe requests over as many messages as maxSize requires. Every
+// message keeps the request id, so acknowledgements still add up.
+func encodeRequest(request *ws.Request, maxSize int) ([]encodedMessage, error) {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	args, ok := request.Args.([]ws.Args)
+	if maxSize <= 0 || len(data) <= maxSize {
+		return []encodedMessage{{data: data, args: len(args)}}, nil
+	}
+
+	if !ok || (request.Op != "subscribe" && request.Op != "unsubscribe") {
+		return nil, fmt.Errorf("%s request of %d bytes exceeds %d", request.Op, len(data), maxSize)
+	}
+
+	chunk := *request
+	chunk.Args = []ws.Args{}
+	empty, err := json.Marshal(&chunk)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []encodedMessage
+	start, size := 0, len(empty)
+	flush := func(end int) error {
+		chunk.Args = args[start:end]
+		message, err := json.Marshal(&chunk)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, encodedMessage{data: message, args: end - start})
+		start, size = end, len(empty)
+		return nil
+	}
+	for i, arg := range args {
+		encoded, err := json.Marshal(arg)
+		if err != nil {
+			return nil, err
+		}
+		argSize := len(encoded)
+		if i > start {
+			argSize++ // separating comma
+		}
+		if len(empty)+len(encoded) > maxSize {
+			return nil, fmt.Errorf("arg %v alone exceeds %d bytes", arg, maxSize)
+		}
+		if size+argSize > maxSize {
+			if err := flush(i); err != nil {
+				return nil, err
+			}
+			argSize = len(encoded)
+		}
+		size += argSize
+	}
+	if err := flush(len(args)); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// SetThrottle replaces the pacing of outbound requests. A nil config writes
+// every request immediately.
+func (client *WSClient) SetThrottle(config *ThrottleConfig) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	client.throttle = newThrottle(config)
+}
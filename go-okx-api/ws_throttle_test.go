@@ -0,0 +1,162 @@
+package okx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/ws"
+)
+
+func TestEncodeRequestSplits(t *testing.T) {
+	args := make([]ws.Args, 100)
+	for i := range args {
+		args[i] = ws.Args{Channel: "tickers", InstId: fmt.Sprintf("COIN%d-USDT", i)}
+	}
+	const maxSize = 512
+	request := &ws.Request{Id: "7", Op: "subscribe", Args: args}
+	messages, err := encodeRequest(request, maxSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) < 2 {
+		t.Fatalf("%d messages, want the request split", len(messages))
+	}
+	var sent []ws.Args
+	for _, message := range messages {
+		if len(message.data) > maxSize {
+			t.Errorf("message of %d bytes exceeds %d", len(message.data), maxSize)
+		}
+		var chunk struct {
+			Id   string    `json:"id"`
+			Args []ws.Args `json:"args"`
+		}
+		if err := json.Unmarshal(message.data, &chunk); err != nil {
+			t.Fatal(err)
+		}
+		if chunk.Id != request.Id {
+			t.Errorf("message has id %q, want %q", chunk.Id, request.Id)
+		}
+		if len(chunk.Args) != message.args {
+			t.Errorf("message counts %d args, has %d", message.args, len(chunk.Args))
+		}
+		sent = append(sent, chunk.Args...)
+	}
+	if len(sent) != len(args) {
+		t.Fatalf("%d args sent, want %d", len(sent), len(args))
+	}
+	for i := range args {
+		if sent[i] != args[i] {
+			t.Fatalf("arg %d is %v, want %v", i, sent[i], args[i])
+		}
+	}
+
+	if _, err := encodeRequest(&ws.Request{Op: "order", Args: args}, maxSize); err == nil {
+		t.Fatal("an oversized trading op was split")
+	}
+	if _, err := encodeRequest(&ws.Request{Op: "subscribe", Args: args[:1]}, 10); err == nil {
+		t.Fatal("an arg larger than the limit was accepted")
+	}
+}
+
+func TestPace(t *testing.T) {
+	windows := []window{{50 * time.Millisecond, 2}}
+	var sent []time.Time
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		var err error
+		if sent, err = pace(context.Background(), sent, windows); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if waited := time.Since(start); waited < 50*time.Millisecond {
+		t.Fatalf("third request let through after %v", waited)
+	}
+	if len(sent) == 0 || len(sent) > 2 {
+		t.Fatalf("%d requests remembered, want those of the last window", len(sent))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := pace(ctx, sent, []window{{time.Hour, 1}}); err != context.Canceled {
+		t.Fatalf("got %v, want the context's error", err)
+	}
+}
+
+func TestThrottledSubscribeHonoursContext(t *testing.T) {
+	f := newFakeOKX(t)
+	client := newTestClient(t, f)
+	client.SetThrottle(&ThrottleConfig{OpsPerHour: 1})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := client.SubscribeAndWait(ctx, tickerArgs("BTC-USDT")); err != nil {
+		t.Fatal(err)
+	}
+
+	// the hourly window is used up, so the next request would wait an hour
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := client.SubscribeAndWait(ctx, tickerArgs("ETH-USDT"))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want the context's error", err)
+	}
+	if waited := time.Since(start); waited > time.Second {
+		t.Fatalf("returned after %v", waited)
+	}
+	if n := len(f.Requests("subscribe")); n != 1 {
+		t.Fatalf("%d subscribes sent, want 1", n)
+	}
+}
+
+func TestSplitSubscribeTracksSentArgs(t *testing.T) {
+	f := newFakeOKX(t)
+	client := newTestClient(t, f)
+	// one arg per message, and the second message would wait an hour
+	client.SetThrottle(&ThrottleConfig{OpsPerHour: 1, MaxMessageSize: 100})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	args := tickerArgs("BTC-USDT", "ETH-USDT")
+	if err := client.SubscribeAndWait(ctx, args); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want the context's error", err)
+	}
+	if subs := client.Subscriptions(); len(subs) != 1 || subs[0] != args[0] {
+		t.Fatalf("tracked %v, want the arg sent, %v", subs, args[0])
+	}
+	eventually(t, func() bool { return len(f.Requests("subscribe")) == 1 }, "first message not received")
+}
+
+func TestLoginDroppedWhenAttemptSettles(t *testing.T) {
+	f := newFakeOKX(t)
+	client := newTestClient(t, f)
+	client.SetThrottle(&ThrottleConfig{OpsPerHour: 1})
+	client.throttle.acquire(context.Background(), "login")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := client.LoginContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want the context's error", err)
+	}
+	attempt := client.pendingLogin()
+	if attempt == nil {
+		t.Fatal("login attempt ended with its caller")
+	}
+
+	// the login still waiting for the throttle leaves the queue
+	client.loginResult(attempt, ErrLoginTimeout)
+	eventually(t, func() bool {
+		if !client.throttle.controlMu.TryLock() {
+			return false
+		}
+		client.throttle.controlMu.Unlock()
+		return true
+	}, "login request still queued")
+	if n := len(f.Requests("login")); n != 0 {
+		t.Fatalf("%d logins sent, want 0", n)
+	}
+}
@@ -0,0 +1,193 @@
+package okx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/rest/trade"
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/ws"
+)
+
+// MaxBatchOrders is the most orders OKX accepts in one batch op.
+const MaxBatchOrders = 20
+
+// OpOption customises a trading op sent over the websocket.
+type OpOption func(*ws.Request)
+
+// WithExpTime makes OKX reject the op if it has not been processed by t.
+func WithExpTime(t time.Time) OpOption {
+	return func(r *ws.Request) {
+		r.ExpTime = strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
+	}
+}
+
+// OpFuture is the pending reply to a trading op, correlated by request id.
+type OpFuture struct {
+	Id string
+	Op string
+
+	client *WSClient
+	done   chan struct{}
+	once   sync.Once
+	resp   *ws.OpResponse
+	err    error
+}
+
+// Done is closed once the reply has arrived or the op has failed.
+func (f *OpFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Response waits for the reply to the op. Without a deadline on ctx it waits
+// up to AckTimeout, after which ErrAckTimeout is returned and a late reply is
+// discarded.
+func (f *OpFuture) Response(ctx context.Context) (*ws.OpResponse, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, AckTimeout)
+		defer cancel()
+	}
+	select {
+	case <-f.done:
+		return f.resp, f.err
+	case <-ctx.Done():
+		f.client.forgetOp(f.Id)
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%s %s: %w", f.Op, f.Id, ErrAckTimeout)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// Wait waits for the reply and returns the outcome of every order of the op
+// in request order. If any order was rejected the results come with a
+// ws.OKXError, the rejection of a single order being its own sCode and sMsg;
+// each result's Err tells which orders failed. mass-cancel has no per-order
+// results.
+func (f *OpFuture) Wait(ctx context.Context) ([]ws.OrderResult, error) {
+	resp, err := f.Response(ctx)
+	if err != nil {
+		return nil, err
+	}
+	results, err := resp.OrderResults()
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s results: %v", resp.Op, err)
+	}
+	if resp.IsOk() {
+		return results, nil
+	}
+	if len(results) == 1 && results[0].Err() != nil {
+		return results, results[0].Err()
+	}
+	return results, ws.NewOKXError(resp.Code, resp.Msg)
+}
+
+func (f *OpFuture) complete(resp *ws.OpResponse, err error) {
+	f.once.Do(func() {
+		f.resp = resp
+		f.err = err
+		close(f.done)
+	})
+}
+
+// PlaceOrder places an order over the private websocket. Like RestClient.Do it
+// fills in the client's Tag and a generated clOrdId where they are missing.
+func (client *WSClient) PlaceOrder(ctx context.Context, param *trade.PlaceOrderParam, opts ...OpOption) (*OpFuture, error) {
+	injectOrderIds(param, client.ClOrdIds, client.Tag)
+	return client.sendOp(ctx, "order", []interface{}{param}, opts)
+}
+
+func (client *WSClient) BatchOrders(ctx context.Context, params []trade.PlaceOrderParam, opts ...OpOption) (*OpFuture, error) {
+	if err := checkBatch(len(params)); err != nil {
+		return nil, err
+	}
+	injectOrderIds(params, client.ClOrdIds, client.Tag)
+	return client.sendOp(ctx, "batch-orders", params, opts)
+}
+
+func (client *WSClient) CancelOrder(ctx context.Context, param *trade.CancelOrderParam, opts ...OpOption) (*OpFuture, error) {
+	return client.sendOp(ctx, "cancel-order", []interface{}{param}, opts)
+}
+
+func (client *WSClient) BatchCancelOrders(ctx context.Context, params []trade.CancelOrderParam, opts ...OpOption) (*OpFuture, error) {
+	if err := checkBatch(len(params)); err != nil {
+		return nil, err
+	}
+	return client.sendOp(ctx, "batch-cancel-orders", params, opts)
+}
+
+func (client *WSClient) AmendOrder(ctx context.Context, param *trade.AmendOrderParam, opts ...OpOption) (*OpFuture, error) {
+	return client.sendOp(ctx, "amend-order", []interface{}{param}, opts)
+}
+
+func (client *WSClient) BatchAmendOrders(ctx context.Context, params []trade.AmendOrderParam, opts ...OpOption) (*OpFuture, error) {
+	if err := checkBatch(len(params)); err != nil {
+		return nil, err
+	}
+	return client.sendOp(ctx, "batch-amend-orders", params, opts)
+}
+
+// MassCancel cancels every MMP pending order of an instrument family.
+func (client *WSClient) MassCancel(ctx context.Context, param *trade.MassCancelParam, opts ...OpOption) (*OpFuture, error) {
+	return client.sendOp(ctx, "mass-cancel", []interface{}{param}, opts)
+}
+
+func checkBatch(n int) error {
+	if n == 0 || n > MaxBatchOrders {
+		return fmt.Errorf("batch must hold between 1 and %d orders, got %d", MaxBatchOrders, n)
+	}
+	return nil
+}
+
+// sendOp logs in if needed and sends a trading op under a fresh request id.
+// ctx bounds the login and the wait for the throttle, not the reply.
+func (client *WSClient) sendOp(ctx context.Context, op string, args interface{}, opts []OpOption) (*OpFuture, error) {
+	if client.endpointType != "private" {
+		return nil, fmt.Errorf("%s is only available on the private websocket", op)
+	}
+	if err := client.LoginContext(ctx); err != nil {
+		return nil, err
+	}
+
+	request := &ws.Request{Id: client.nextRequestId(), Op: op, Args: args}
+	for _, opt := range opts {
+		opt(request)
+	}
+	future := &OpFuture{
+		Id:     request.Id,
+		Op:     op,
+		client: client,
+		done:   make(chan struct{}),
+	}
+
+	client.ackMu.Lock()
+	client.pendingOps[request.Id] = future
+	client.ackMu.Unlock()
+
+	if _, err := client.sendRequest(ctx, request); err != nil {
+		client.forgetOp(request.Id)
+		return nil, err
+	}
+	return future, nil
+}
+
+// resolveOp hands the reply to a trading op to its future.
+func (client *WSClient) resolveOp(resp *ws.OpResponse) {
+	client.ackMu.Lock()
+	future, ok := client.pendingOps[resp.Id]
+	delete(client.pendingOps, resp.Id)
+	client.ackMu.Unlock()
+	if ok {
+		future.complete(resp, nil)
+	}
+}
+
+func (client *WSClient) forgetOp(id string) {
+	client.ackMu.Lock()
+	defer client.ackMu.Unlock()
+	delete(client.pendingOps, id)
+}
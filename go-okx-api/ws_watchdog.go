@@ -0,0 +1,218 @@
+package okx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/ws"
+
+	"github.com/gorilla/websocket"
+)
+
+// EventStale is emitted with a *StaleStream when a subscribed channel has not
+// pushed anything for longer than its threshold.
+const EventStale = "stale"
+
+// DefaultWatchdogInterval is how often streams are checked when the
+// WatchdogConfig does not say.
+const DefaultWatchdogInterval = time.Second
+
+// maxStaleBackoff caps how often the threshold of a stream is doubled
+// because resubscribing it brought no push.
+const maxStaleBackoff = 4
+
+var ErrStaleStream = errors.New("stale stream")
+
+// WatchdogConfig detects subscriptions that stopped updating while their
+// connection stays open.
+type WatchdogConfig struct {
+	// Thresholds overrides the silence allowed per channel, keyed by channel
+	// name or by catalog name for prefixed channels, e.g. "candle" for every
+	// bar size. Zero disables the watchdog for the channel. Channels without
+	// an entry use the StaleAfter of their ChannelSpec.
+	Thresholds map[string]time.Duration
+	// CheckInterval is how often streams are checked, DefaultWatchdogInterval
+	// if zero.
+	CheckInterval time.Duration
+	// Reconnect drops the whole connection when a stream goes stale, instead
+	// of unsubscribing and resubscribing the stream alone. A resubscribe
+	// counts towards the connection's subscribe limits, so each one that
+	// brings no push doubles the threshold of the stream, up to 16 times,
+	// until it pushes again.
+	Reconnect bool
+}
+
+func DefaultWatchdogConfig() *WatchdogConfig {
+	return &WatchdogConfig{CheckInterval: DefaultWatchdogInterval}
+}
+
+// Threshold returns the silence allowed on channel before it is stale, zero
+// if it is never considered stale.
+func (c *WatchdogConfig) Threshold(channel string) time.Duration {
+	if threshold, ok := c.Thresholds[channel]; ok {
+		return threshold
+	}
+	spec, _ := Channels.Lookup(channel)
+	if threshold, ok := c.Thresholds[spec.Name]; ok {
+		return threshold
+	}
+	return spec.StaleAfter
+}
+
+func (c *WatchdogConfig) interval() time.Duration {
+	if c.CheckInterval <= 0 {
+		return DefaultWatchdogInterval
+	}
+	return c.CheckInterval
+}
+
+// StaleStream describes a subscription the watchdog found silent.
+type StaleStream struct {
+	EndpointType string
+	Args         ws.Args
+	// LastUpdate is the time of the last push, or of the first check if the
+	// stream never pushed on this connection.
+	LastUpdate time.Time
+	Silence    time.Duration
+	// Threshold is the silence allowed, raised for streams that stayed
+	// silent after being resubscribed.
+	Threshold time.Duration
+}
+
+// SetWatchdog starts watching every subscription of the client for silence.
+// A nil config stops watching.
+func (client *WSClient) SetWatchdog(config *WatchdogConfig) {
+	client.watchdog.Store(config)
+}
+
+// SetWatchdog applies the watchdog config to every connection of every
+// endpoint.
+func (c *OKXWsClient) SetWatchdog(config *WatchdogConfig) {
+	for _, pool := range c.pools {
+		for _, shard := range pool.Shards() {
+			shard.SetWatchdog(config)
+		}
+	}
+}
+
+// touch records a push on arg while a watchdog is set.
+func (client *WSClient) touch(arg ws.Args, received time.Time) {
+	if client.watchdog.Load() == nil {
+		return
+	}
+	client.streamMu.Lock()
+	client.lastUpdate[arg] = received
+	if len(client.recoveries) > 0 {
+		delete(client.recoveries, arg)
+	}
+	client.streamMu.Unlock()
+}
+
+// resetStreams forgets the pushes of a previous connection.
+func (client *WSClient) resetStreams() {
+	client.streamMu.Lock()
+	client.lastUpdate = make(map[ws.Args]time.Time)
+	client.recoveries = make(map[ws.Args]int)
+	client.streamMu.Unlock()
+}
+
+// watchStreams checks the subscriptions of conn for silence until it drops.
+func (client *WSClient) watchStreams(ctx context.Context, conn *websocket.Conn) {
+	for {
+		interval := DefaultWatchdogInterval
+		if config := client.watchdog.Load(); config != nil {
+			interval = config.interval()
+		}
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return
+		}
+
+		config := client.watchdog.Load()
+		if config == nil || client.State() == StateLoggingIn {
+			continue
+		}
+		stale := client.staleStreams(config, time.Now())
+		if len(stale) > 0 && !client.recoverStreams(ctx, conn, config, stale) {
+			return
+		}
+	}
+}
+
+// staleStreams returns the subscriptions silent for longer than their
+// threshold and restarts their count, so each is reported once per
+// threshold. The threshold doubles with every report since the stream last
+// pushed, so a stream resubscribing does not revive is retried less often.
+func (client *WSClient) staleStreams(config *WatchdogConfig, now time.Time) []*StaleStream {
+	subs := client.trackedSubscriptions()
+
+	client.streamMu.Lock()
+	defer client.streamMu.Unlock()
+	for arg := range client.lastUpdate {
+		if _, ok := subs[arg]; !ok {
+			delete(client.lastUpdate, arg)
+			delete(client.recoveries, arg)
+		}
+	}
+
+	var stale []*StaleStream
+	for arg := range subs {
+		threshold := config.Threshold(arg.Channel)
+		if threshold <= 0 {
+			continue
+		}
+		if n := client.recoveries[arg]; n < maxStaleBackoff {
+			threshold <<= n
+		} else {
+			threshold <<= maxStaleBackoff
+		}
+		last, ok := client.lastUpdate[arg]
+		if !ok {
+			client.lastUpdate[arg] = now
+			continue
+		}
+		if silence := now.Sub(last); silence > threshold {
+			stale = append(stale, &StaleStream{
+				EndpointType: client.endpointType,
+				Args:         arg,
+				LastUpdate:   last,
+				Silence:      silence,
+				Threshold:    threshold,
+			})
+			client.lastUpdate[arg] = now
+			client.recoveries[arg]++
+		}
+	}
+	return stale
+}
+
+// recoverStreams reports the stale streams and resubscribes them, or drops
+// conn if the config says to reconnect. It returns false once conn is gone.
+func (client *WSClient) recoverStreams(ctx context.Context, conn *websocket.Conn, config *WatchdogConfig, stale []*StaleStream) bool {
+	var args []ws.Args
+	for _, stream := range stale {
+		client.log().Warn("stream is stale", "endpoint", client.endpointType, "channel", stream.Args.Channel, "instId", stream.Args.InstId, "silence", stream.Silence)
+		client.Emit(EventStale, stream)
+		args = append(args, stream.Args)
+	}
+
+	if config.Reconnect {
+		client.connectionLost(conn, fmt.Errorf("%w: %d silent subscriptions", ErrStaleStream, len(args)))
+		return false
+	}
+
+	// the registry is left alone, so the streams are still replayed on a
+	// reconnect if resubscribing fails
+	if _, err := client.sendRequest(ctx, ws.NewRequestUnsubscribe(args)); err != nil {
+		client.log().Warn("unsubscribing stale streams failed", "endpoint", client.endpointType, "error", err)
+		return client.IsConnected()
+	}
+	if _, err := client.sendRequest(ctx, ws.NewRequestSubscribe(args)); err != nil {
+		client.log().Warn("resubscribing stale streams failed", "endpoint", client.endpointType, "error", err)
+		return client.IsConnected()
+	}
+	return true
+}
@@ -0,0 +1,41 @@
+package okx
+
+import (
+	"testing"
+	"time"
+
+	"cadenza-market-connector-okx/pkg/go-okx-api/models/ws"
+)
+
+func TestStaleStreamsBackOff(t *testing.T) {
+	client := &WSClient{
+		subscriptions: make(map[ws.Args]bool),
+		lastUpdate:    make(map[ws.Args]time.Time),
+		recoveries:    make(map[ws.Args]int),
+	}
+	arg := ws.Args{Channel: "tickers", InstId: "BTC-USDT"}
+	client.track([]ws.Args{arg}, false)
+	config := &WatchdogConfig{Thresholds: map[string]time.Duration{"tickers": time.Minute}}
+
+	now := time.Now()
+	client.staleStreams(config, now)
+	// every report without a push since doubles the silence allowed
+	for _, threshold := range []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute} {
+		if stale := client.staleStreams(config, now.Add(threshold)); len(stale) != 0 {
+			t.Fatalf("reported after %v of silence", threshold)
+		}
+		now = now.Add(threshold + time.Second)
+		stale := client.staleStreams(config, now)
+		if len(stale) != 1 || stale[0].Threshold != threshold {
+			t.Fatalf("got %+v, want a report with threshold %v", stale, threshold)
+		}
+	}
+
+	// a push restores the configured threshold
+	client.watchdog.Store(config)
+	client.touch(arg, now)
+	now = now.Add(time.Minute + time.Second)
+	if stale := client.staleStreams(config, now); len(stale) != 1 || stale[0].Threshold != time.Minute {
+		t.Fatalf("got %+v after a push, want a report with threshold %v", stale, time.Minute)
+	}
+}